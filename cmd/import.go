@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	config "github.com/liju-github/FoodBuddyMicroserviceRestaurant/configs"
+	"github.com/liju-github/FoodBuddyMicroserviceRestaurant/db"
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"github.com/liju-github/FoodBuddyMicroserviceRestaurant/repository"
+	"github.com/liju-github/FoodBuddyMicroserviceRestaurant/service"
+)
+
+// runImport implements the import-products subcommand:
+//
+//	restaurant import-products -restaurant rest_... -file menu.csv [-format json] [-dry-run]
+//
+// It exits non-zero when the file has errors, printing each one with its line.
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import-products", flag.ExitOnError)
+	restaurantID := flags.String("restaurant", "", "ID of the restaurant to import into")
+	file := flags.String("file", "", "CSV or JSON file to import")
+	format := flags.String("format", "", "csv or json; defaults to the file extension")
+	dryRun := flags.Bool("dry-run", false, "validate and report without saving")
+	flags.Parse(args)
+
+	if *restaurantID == "" || *file == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+
+	config := config.LoadConfig()
+	database, err := db.Connect(config)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close(database)
+
+	svc := service.NewRestaurantService(repository.NewRestaurantRepository(database), config.JWTSecretKey, service.LogNotifier{})
+
+	ctx := model.WithActor(context.Background(), "cli")
+	result, err := svc.BulkImportProducts(ctx, *restaurantID, *format, data, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	for _, e := range result.Errors {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s\n", *file, e.Line, e.Message)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "%d errors; nothing imported\n", len(result.Errors))
+		os.Exit(1)
+	}
+
+	mode := "Imported"
+	if result.DryRun {
+		mode = "Dry run"
+	}
+	fmt.Printf("%s: %d created, %d updated, %d unchanged\n", mode, result.Created, result.Updated, result.Unchanged)
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
+	"os"
+	"time"
+	_ "time/tzdata" // restaurant timezones must resolve in minimal containers
 
 	"google.golang.org/grpc"
 
@@ -15,6 +19,11 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import-products" {
+		runImport(os.Args[2:])
+		return
+	}
+
 	// Load configurations
 	config := config.LoadConfig()
 
@@ -33,7 +42,14 @@ func main() {
 	repo := repository.NewRestaurantRepository(db)
 
 	// Initialize service
-	svc := service.NewRestaurantService(repo)
+	svc := service.NewRestaurantService(repo, config.AppealSecretKey, service.LogNotifier{})
+
+	// Start background jobs
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	svc.StartSuspensionExpiryJob(jobCtx, time.Minute)
+	svc.StartKYCExpiryJob(jobCtx, time.Hour, config.KYCAutoSuspend)
+	svc.StartProductPurgeJob(jobCtx, 24*time.Hour, time.Duration(config.ProductRetention)*24*time.Hour)
 
 	// Initialize gRPC server
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", config.RESTAURANTGRPCPORT))
@@ -41,7 +57,7 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(service.ActorInterceptor(config.JWTSecretKey)))
 	restaurantPb.RegisterRestaurantServiceServer(grpcServer, svc)
 
 	log.Printf("Restaurant Service starting on port %s", config.RESTAURANTGRPCPORT)
@@ -3,32 +3,48 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DBUser       string
-	DBPassword   string
-	DBName       string
-	DBHost       string
-	DBPort       string
+	DBUser             string
+	DBPassword         string
+	DBName             string
+	DBHost             string
+	DBPort             string
 	RESTAURANTGRPCPORT string
-	JWTSecretKey string
+	JWTSecretKey       string
+	AppealSecretKey    string
+	KYCAutoSuspend     bool
+	ProductRetention   int
 }
 
+// defaultProductRetentionDays is how long soft-deleted products are kept when
+// PRODUCTRETENTIONDAYS is not set.
+const defaultProductRetentionDays = 30
+
 func LoadConfig() Config {
 	if err := godotenv.Load(".env"); err != nil {
 		log.Fatal("No .env file found")
 	}
 
+	retention, err := strconv.Atoi(os.Getenv("PRODUCTRETENTIONDAYS"))
+	if err != nil || retention <= 0 {
+		retention = defaultProductRetentionDays
+	}
+
 	return Config{
-		DBUser:       os.Getenv("DBUSER"),
-		DBPassword:   os.Getenv("DBPASSWORD"),
-		DBName:       os.Getenv("DBNAME"),
-		DBHost:       os.Getenv("DBHOST"),
-		DBPort:       os.Getenv("DBPORT"),
+		DBUser:             os.Getenv("DBUSER"),
+		DBPassword:         os.Getenv("DBPASSWORD"),
+		DBName:             os.Getenv("DBNAME"),
+		DBHost:             os.Getenv("DBHOST"),
+		DBPort:             os.Getenv("DBPORT"),
 		RESTAURANTGRPCPORT: os.Getenv("RESTAURANTGRPCPORT"),
-		JWTSecretKey: os.Getenv("JWTSECRET"),
+		JWTSecretKey:       os.Getenv("JWTSECRET"),
+		AppealSecretKey:    os.Getenv("APPEALSECRET"),
+		KYCAutoSuspend:     os.Getenv("KYCAUTOSUSPEND") == "true",
+		ProductRetention:   retention,
 	}
 }
@@ -5,8 +5,10 @@ import (
 	"log"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	config "github.com/liju-github/FoodBuddyMicroserviceRestaurant/configs"
 	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
@@ -23,7 +25,13 @@ func Connect(cfg config.Config) (*gorm.DB, error) {
 		cfg.DBName,
 	)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	// Restaurant.Products is only used for preloading; products predate any
+	// foreign key and existing rows may not satisfy one. Translated errors let the
+	// repository tell unique index violations apart as gorm.ErrDuplicatedKey.
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		TranslateError:                           true,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -39,18 +47,238 @@ func Connect(cfg config.Config) (*gorm.DB, error) {
 	sqlDB.SetConnMaxLifetime(5 * time.Minute)
 	sqlDB.SetConnMaxIdleTime(5 * time.Minute)
 
+	// Product SKUs and branch owner emails used to be stored as "" when absent.
+	// They are NULL now, so the unique indexes created below only cover rows that
+	// have one; this has to run before AutoMigrate adds those indexes.
+	for _, c := range []struct {
+		model  interface{}
+		table  string
+		column string
+	}{
+		{&model.Product{}, "products", "sku"},
+		{&model.Restaurant{}, "restaurants", "owner_email"},
+	} {
+		if !db.Migrator().HasColumn(c.model, c.column) {
+			continue
+		}
+		stmt := fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s = ''", c.table, c.column, c.column)
+		if err := db.Exec(stmt).Error; err != nil {
+			return nil, fmt.Errorf("failed to clear empty %s.%s: %w", c.table, c.column, err)
+		}
+	}
+
+	if err := dedupePromotionUsage(db); err != nil {
+		return nil, fmt.Errorf("failed to dedupe promotion usage: %w", err)
+	}
+
 	// Auto-migrate database schema for all models
 	if err := db.AutoMigrate(
 		&model.Restaurant{},
 		&model.Product{},
+		&model.OpeningHours{},
+		&model.HolidayClosure{},
+		&model.DeliveryZone{},
+		&model.Brand{},
+		&model.BrandMenuItem{},
+		&model.RestaurantStatusTransition{},
+		&model.BanRecord{},
+		&model.BanAppeal{},
+		&model.KYCDocument{},
+		&model.Review{},
+		&model.Tag{},
+		&model.RestaurantCuisine{},
+		&model.ProductTag{},
+		&model.TaxClass{},
+		&model.Promotion{},
+		&model.PromotionUsage{},
+		&model.Coupon{},
+		&model.CouponRedemption{},
+		&model.PriceSchedule{},
+		&model.ProductVersion{},
 	); err != nil {
 		return nil, fmt.Errorf("auto-migration failed: %w", err)
 	}
 
+	// Rows created before timestamps existed sort as the oldest ones, and count
+	// as last updated when they were created. UpdateColumn skips the audit hooks.
+	for _, m := range []interface{}{&model.Restaurant{}, &model.Product{}} {
+		if err := db.Unscoped().Model(m).Where("created_at IS NULL").UpdateColumn("created_at", time.Unix(0, 0)).Error; err != nil {
+			return nil, fmt.Errorf("failed to backfill created_at: %w", err)
+		}
+		if err := db.Unscoped().Model(m).Where("updated_at IS NULL").UpdateColumn("updated_at", gorm.Expr("created_at")).Error; err != nil {
+			return nil, fmt.Errorf("failed to backfill updated_at: %w", err)
+		}
+	}
+
+	// Seed the controlled tag vocabulary; existing tags are left as they are
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.DefaultTags).Error; err != nil {
+		return nil, fmt.Errorf("failed to seed tags: %w", err)
+	}
+
+	if err := renameTags(db); err != nil {
+		return nil, fmt.Errorf("failed to rename tags: %w", err)
+	}
+
+	if err := backfillLegacyBans(db); err != nil {
+		return nil, fmt.Errorf("failed to backfill legacy bans: %w", err)
+	}
+
+	if err := migrateLegacyAmounts(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate legacy amounts: %w", err)
+	}
+
+	if err := ensureSearchIndexes(db); err != nil {
+		return nil, fmt.Errorf("failed to create search indexes: %w", err)
+	}
+
 	log.Println("Connected to MySQL database and schema migrated")
 	return db, nil
 }
 
+// renamedTags maps tag IDs that were seeded under a wrong slug to the slug in
+// DefaultTags, which the seed above has already created. Slugs use underscores
+// like the rest of the vocabulary; gluten-free was briefly seeded with a hyphen.
+var renamedTags = map[string]string{
+	"gluten-free": "gluten_free",
+}
+
+// renameTags moves product links from each old tag ID to its new one and drops
+// the old tag. A product linked to both keeps only its link to the new one, since
+// moving the other would repeat the (product_id, tag_id) primary key.
+func renameTags(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for oldID, newID := range renamedTags {
+			var linked []string
+			if err := tx.Model(&model.ProductTag{}).Where("tag_id = ?", newID).Pluck("product_id", &linked).Error; err != nil {
+				return err
+			}
+			if len(linked) > 0 {
+				err := tx.Where("tag_id = ? AND product_id IN ?", oldID, linked).Delete(&model.ProductTag{}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			err := tx.Model(&model.ProductTag{}).
+				Where("tag_id = ?", oldID).
+				Update("tag_id", newID).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Where("id = ?", oldID).Delete(&model.Tag{}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// dedupePromotionUsage prepares promotion_usages for the unique index on
+// (promotion_id, order_id) that AutoMigrate adds. Usages recorded without an
+// order get a placeholder order of their own so they keep counting against the
+// customer's limit, and repeated usages of a promotion by one order are reduced
+// to the first.
+func dedupePromotionUsage(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&model.PromotionUsage{}) ||
+		db.Migrator().HasIndex(&model.PromotionUsage{}, "idx_promo_usage_order") {
+		return nil
+	}
+	err := db.Exec("UPDATE promotion_usages SET order_id = CONCAT('legacy_', id) WHERE order_id = '' OR order_id IS NULL").Error
+	if err != nil {
+		return err
+	}
+	return db.Exec("DELETE u FROM promotion_usages u JOIN promotion_usages k " +
+		"ON k.promotion_id = u.promotion_id AND k.order_id = u.order_id AND k.id < u.id").Error
+}
+
+// backfillLegacyBans records a ban for every restaurant flagged as banned
+// without an open ban record, which is how bans were stored before ban history
+// existed. They are attributed to an admin, like the ban RPC still does, and
+// dated to the restaurant's last update as the closest known time.
+func backfillLegacyBans(db *gorm.DB) error {
+	var restaurants []*model.Restaurant
+	err := db.Select("id", "ban_reason", "updated_at").
+		Where("is_banned = ?", true).
+		Where("NOT EXISTS (SELECT 1 FROM ban_records WHERE ban_records.restaurant_id = restaurants.id AND ban_records.lifted_at IS NULL)").
+		Find(&restaurants).Error
+	if err != nil {
+		return err
+	}
+
+	for _, r := range restaurants {
+		ban := &model.BanRecord{
+			ID:           fmt.Sprintf("ban_%s", uuid.New().String()),
+			RestaurantID: r.ID,
+			IssuedBy:     model.ActorAdmin,
+			Category:     model.BanCategoryOther,
+			Reason:       r.BanReason,
+			StartsAt:     r.UpdatedAt,
+		}
+		if err := db.Create(ban).Error; err != nil {
+			return fmt.Errorf("restaurant %s: %w", r.ID, err)
+		}
+	}
+	return nil
+}
+
+// migrateLegacyAmounts copies the legacy floating-point money columns into their
+// *_minor replacements. Every restaurant priced in INR before currencies existed,
+// so the values are rounded to paise. The legacy columns are kept for rollback.
+func migrateLegacyAmounts(db *gorm.DB) error {
+	columns := []struct {
+		model  interface{}
+		table  string
+		legacy string
+		minor  string
+	}{
+		{&model.Product{}, "products", "price", "price_minor"},
+		{&model.BrandMenuItem{}, "brand_menu_items", "price", "price_minor"},
+		{&model.DeliveryZone{}, "delivery_zones", "min_order", "min_order_minor"},
+		{&model.DeliveryZone{}, "delivery_zones", "delivery_fee", "delivery_fee_minor"},
+	}
+
+	for _, c := range columns {
+		if !db.Migrator().HasColumn(c.model, c.legacy) {
+			continue
+		}
+		stmt := fmt.Sprintf("UPDATE %s SET %s = ROUND(%s * 100) WHERE %s IS NULL AND %s IS NOT NULL",
+			c.table, c.minor, c.legacy, c.minor, c.legacy)
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("%s.%s: %w", c.table, c.legacy, err)
+		}
+	}
+	return nil
+}
+
+// ensureSearchIndexes creates the FULLTEXT indexes used by catalog search. Other
+// drivers have no equivalent, so search falls back to LIKE matching there.
+func ensureSearchIndexes(db *gorm.DB) error {
+	if db.Dialector.Name() != "mysql" {
+		return nil
+	}
+
+	indexes := []struct {
+		model   interface{}
+		table   string
+		name    string
+		columns string
+	}{
+		{&model.Product{}, "products", "ft_products_search", "name, description, category"},
+		{&model.Restaurant{}, "restaurants", "ft_restaurants_search", "name, locality"},
+	}
+
+	for _, idx := range indexes {
+		if db.Migrator().HasIndex(idx.model, idx.name) {
+			continue
+		}
+		stmt := fmt.Sprintf("CREATE FULLTEXT INDEX %s ON %s (%s)", idx.name, idx.table, idx.columns)
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close terminates the MySQL database connection safely.
 func Close(db *gorm.DB) {
 	if db == nil {
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Ban appeal states.
+const (
+	AppealPending  = "pending"
+	AppealAccepted = "accepted"
+	AppealRejected = "rejected"
+)
+
+// BanAppeal is a banned restaurant's request to have a specific ban lifted.
+type BanAppeal struct {
+	ID            string     `gorm:"column:id;size:100" json:"id"`
+	RestaurantID  string     `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	BanID         string     `gorm:"column:ban_id;size:100;index" json:"banId"`
+	Message       string     `gorm:"column:message;type:text" json:"message"`
+	Status        string     `gorm:"column:status;size:20;index" json:"status"`
+	ReviewerID    string     `gorm:"column:reviewer_id;size:100" json:"reviewerId"`
+	ReviewComment string     `gorm:"column:review_comment;type:text" json:"reviewComment"`
+	CreatedAt     time.Time  `gorm:"column:created_at" json:"createdAt"`
+	ReviewedAt    *time.Time `gorm:"column:reviewed_at" json:"reviewedAt"`
+}
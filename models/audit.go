@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ActorSystem is recorded for changes made by background jobs and migrations.
+const ActorSystem = "system"
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes changes made with it to actorID.
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+// ActorFromContext returns the actor set by WithActor, or "" when there is none.
+func ActorFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// BeforeCreate records who created the restaurant.
+func (r *Restaurant) BeforeCreate(tx *gorm.DB) error {
+	stampCreate(tx)
+	return nil
+}
+
+// BeforeUpdate records who last changed the restaurant.
+func (r *Restaurant) BeforeUpdate(tx *gorm.DB) error {
+	stampUpdate(tx)
+	return nil
+}
+
+// BeforeCreate records who created the product.
+func (p *Product) BeforeCreate(tx *gorm.DB) error {
+	stampCreate(tx)
+	return nil
+}
+
+// BeforeUpdate records who last changed the product.
+func (p *Product) BeforeUpdate(tx *gorm.DB) error {
+	stampUpdate(tx)
+	return nil
+}
+
+// stampCreate fills created_by and updated_by from the actor on the statement's
+// context. SetColumn works for both struct and map writes, including batches.
+// Without an actor the columns are left as the caller set them.
+func stampCreate(tx *gorm.DB) {
+	actor := ActorFromContext(tx.Statement.Context)
+	if actor == "" {
+		return
+	}
+	tx.Statement.SetColumn("created_by", actor)
+	tx.Statement.SetColumn("updated_by", actor)
+}
+
+// stampUpdate fills updated_by from the actor on the statement's context.
+func stampUpdate(tx *gorm.DB) {
+	actor := ActorFromContext(tx.Statement.Context)
+	if actor == "" {
+		return
+	}
+	tx.Statement.SetColumn("updated_by", actor)
+}
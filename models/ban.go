@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// Ban reason categories.
+const (
+	BanCategoryFraud              = "fraud"
+	BanCategoryHygiene            = "hygiene"
+	BanCategoryPolicyViolation    = "policy_violation"
+	BanCategoryCustomerComplaints = "customer_complaints"
+	BanCategoryCompliance         = "compliance"
+	BanCategoryOther              = "other"
+)
+
+// ActorAdmin is recorded as the issuer when a ban arrives without a known admin.
+const ActorAdmin = "admin"
+
+var banCategories = map[string]bool{
+	BanCategoryFraud:              true,
+	BanCategoryHygiene:            true,
+	BanCategoryPolicyViolation:    true,
+	BanCategoryCustomerComplaints: true,
+	BanCategoryCompliance:         true,
+	BanCategoryOther:              true,
+}
+
+// ValidBanCategory reports whether category is one of the known ban categories.
+func ValidBanCategory(category string) bool {
+	return banCategories[category]
+}
+
+// BanRecord is one enforcement action against a restaurant. EndsAt is nil for an
+// indefinite ban; LiftedAt is set once the ban is over, whether it expired or was
+// lifted by hand.
+type BanRecord struct {
+	ID           string     `gorm:"column:id;size:100" json:"id"`
+	RestaurantID string     `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	IssuedBy     string     `gorm:"column:issued_by;size:100" json:"issuedBy"`
+	Category     string     `gorm:"column:category;size:50" json:"category"`
+	Reason       string     `gorm:"column:reason;type:text" json:"reason"`
+	StartsAt     time.Time  `gorm:"column:starts_at" json:"startsAt"`
+	EndsAt       *time.Time `gorm:"column:ends_at;index" json:"endsAt"`
+	LiftedAt     *time.Time `gorm:"column:lifted_at;index" json:"liftedAt"`
+	LiftedBy     string     `gorm:"column:lifted_by;size:100" json:"liftedBy"`
+	LiftNote     string     `gorm:"column:lift_note;type:text" json:"liftNote"`
+}
+
+// IsActive reports whether the ban is in force at now.
+func (b *BanRecord) IsActive(now time.Time) bool {
+	if b.LiftedAt != nil {
+		return false
+	}
+	return b.EndsAt == nil || now.Before(*b.EndsAt)
+}
@@ -0,0 +1,52 @@
+package model
+
+// Brand is an organisation that owns several branch restaurants under one owner login.
+type Brand struct {
+	ID           string `gorm:"column:id;size:100" json:"id"`
+	Name         string `gorm:"column:name" json:"name"`
+	OwnerEmail   string `gorm:"column:owner_email;size:191;uniqueIndex" json:"ownerEmail"`
+	PasswordHash string `gorm:"column:password_hash" json:"passwordHash"`
+}
+
+// BrandMenuItem is an entry of a brand's master menu. Copying the menu to a branch
+// creates or refreshes a Product linked back through Product.BrandMenuItemID.
+type BrandMenuItem struct {
+	ID          string `gorm:"column:id;size:100" json:"id"`
+	BrandID     string `gorm:"column:brand_id;size:100;index" json:"brandId"`
+	Name        string `gorm:"column:name" json:"name"`
+	Description string `gorm:"column:description" json:"description"`
+	Price       Amount `gorm:"column:price_minor" json:"price"`
+	Category    string `gorm:"column:category" json:"category"`
+}
+
+// Validate applies the product rules to the item, since every branch gets a
+// product copied from it.
+func (i *BrandMenuItem) Validate() error {
+	patch := ProductPatch{Name: &i.Name, Description: &i.Description, Price: &i.Price, Category: &i.Category}
+	return patch.Validate()
+}
+
+// BranchPriceOverrides maps branch restaurant ID to brand menu item ID to price.
+type BranchPriceOverrides map[string]map[string]Amount
+
+// Price returns the override for item at branch, or the master price.
+func (o BranchPriceOverrides) Price(branchID string, item *BrandMenuItem) Amount {
+	if prices, ok := o[branchID]; ok {
+		if price, ok := prices[item.ID]; ok {
+			return price
+		}
+	}
+	return item.Price
+}
+
+// Validate rejects negative override prices.
+func (o BranchPriceOverrides) Validate() error {
+	for _, prices := range o {
+		for _, price := range prices {
+			if price < 0 {
+				return ErrInvalidAmount
+			}
+		}
+	}
+	return nil
+}
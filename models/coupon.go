@@ -0,0 +1,98 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Coupon discount types.
+const (
+	CouponPercent = "percent"
+	CouponFlat    = "flat"
+)
+
+// Coupon is a code customers enter at checkout. UsageCap limits redemptions across
+// all customers and PerUserCap per customer; zero means unlimited. Redemptions
+// keeps the running total so the cap can be enforced with a single guarded update.
+type Coupon struct {
+	ID                 string     `gorm:"column:id;size:100" json:"id"`
+	RestaurantID       string     `gorm:"column:restaurant_id;size:100;uniqueIndex:idx_coupon_restaurant_code" json:"restaurantId"`
+	Code               string     `gorm:"column:code;size:50;uniqueIndex:idx_coupon_restaurant_code" json:"code"`
+	DiscountType       string     `gorm:"column:discount_type;size:20" json:"discountType"`
+	PercentBasisPoints int        `gorm:"column:percent_bps" json:"percentBasisPoints"`
+	FlatAmount         Amount     `gorm:"column:flat_amount_minor" json:"flatAmount"`
+	MaxDiscount        Amount     `gorm:"column:max_discount_minor" json:"maxDiscount"`
+	MinOrder           Amount     `gorm:"column:min_order_minor" json:"minOrder"`
+	UsageCap           int        `gorm:"column:usage_cap" json:"usageCap"`
+	PerUserCap         int        `gorm:"column:per_user_cap" json:"perUserCap"`
+	Redemptions        int        `gorm:"column:redemptions" json:"redemptions"`
+	ExpiresAt          *time.Time `gorm:"column:expires_at" json:"expiresAt"`
+	Active             bool       `gorm:"column:active" json:"active"`
+}
+
+// CouponRedemption records one use of a coupon on an order.
+type CouponRedemption struct {
+	ID         uint      `gorm:"column:id;primaryKey" json:"id"`
+	CouponID   string    `gorm:"column:coupon_id;size:100;index:idx_coupon_redemption_customer" json:"couponId"`
+	CustomerID string    `gorm:"column:customer_id;size:100;index:idx_coupon_redemption_customer" json:"customerId"`
+	OrderID    string    `gorm:"column:order_id;size:100;uniqueIndex" json:"orderId"`
+	Discount   Amount    `gorm:"column:discount_minor" json:"discount"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"createdAt"`
+}
+
+// NormalizeCouponCode makes codes case-insensitive and trims stray whitespace.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks the coupon definition.
+func (c *Coupon) Validate() error {
+	if c.Code == "" || c.MinOrder < 0 || c.MaxDiscount < 0 || c.UsageCap < 0 || c.PerUserCap < 0 {
+		return ErrInvalidCoupon
+	}
+	switch c.DiscountType {
+	case CouponPercent:
+		if c.PercentBasisPoints <= 0 || c.PercentBasisPoints > 10000 {
+			return ErrInvalidCoupon
+		}
+	case CouponFlat:
+		if c.FlatAmount <= 0 {
+			return ErrInvalidCoupon
+		}
+	default:
+		return ErrInvalidCoupon
+	}
+	return nil
+}
+
+// Check reports whether the coupon can be used on an order of orderTotal at now,
+// ignoring usage caps, which are enforced when redeeming.
+func (c *Coupon) Check(orderTotal Amount, now time.Time) error {
+	if !c.Active {
+		return ErrCouponInactive
+	}
+	if c.ExpiresAt != nil && !now.Before(*c.ExpiresAt) {
+		return ErrCouponExpired
+	}
+	if orderTotal < c.MinOrder {
+		return ErrCouponMinOrder
+	}
+	return nil
+}
+
+// Discount is the amount the coupon takes off an order of orderTotal.
+func (c *Coupon) Discount(orderTotal Amount) Amount {
+	var d Amount
+	if c.DiscountType == CouponPercent {
+		d = roundDiv(orderTotal*Amount(c.PercentBasisPoints), 10000)
+		if c.MaxDiscount > 0 && d > c.MaxDiscount {
+			d = c.MaxDiscount
+		}
+	} else {
+		d = c.FlatAmount
+	}
+	if d > orderTotal {
+		d = orderTotal
+	}
+	return d
+}
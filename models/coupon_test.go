@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCouponDiscount(t *testing.T) {
+	tests := []struct {
+		name       string
+		coupon     Coupon
+		orderTotal Amount
+		want       Amount
+	}{
+		{"percent", Coupon{DiscountType: CouponPercent, PercentBasisPoints: 1000}, 45000, 4500},
+		{"percent rounds half up", Coupon{DiscountType: CouponPercent, PercentBasisPoints: 1250}, 1004, 126},
+		{"percent under the cap", Coupon{DiscountType: CouponPercent, PercentBasisPoints: 2000, MaxDiscount: 10000}, 30000, 6000},
+		{"percent capped", Coupon{DiscountType: CouponPercent, PercentBasisPoints: 2000, MaxDiscount: 10000}, 80000, 10000},
+		{"percent with no cap", Coupon{DiscountType: CouponPercent, PercentBasisPoints: 5000}, 80000, 40000},
+		{"flat", Coupon{DiscountType: CouponFlat, FlatAmount: 7500}, 30000, 7500},
+		{"flat clamped to the order", Coupon{DiscountType: CouponFlat, FlatAmount: 7500}, 5000, 5000},
+		{"full percent on an empty order", Coupon{DiscountType: CouponPercent, PercentBasisPoints: 10000}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.coupon.Discount(tt.orderTotal); got != tt.want {
+				t.Errorf("Discount(%d) = %d, want %d", tt.orderTotal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCouponCheck(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Minute)
+	expiresNow := now
+	later := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		coupon  Coupon
+		total   Amount
+		wantErr error
+	}{
+		{"usable", Coupon{Active: true, ExpiresAt: &later, MinOrder: 20000}, 20000, nil},
+		{"inactive", Coupon{Active: false}, 20000, ErrCouponInactive},
+		{"expired", Coupon{Active: true, ExpiresAt: &expired}, 20000, ErrCouponExpired},
+		{"expiry is exclusive", Coupon{Active: true, ExpiresAt: &expiresNow}, 20000, ErrCouponExpired},
+		{"below the minimum order", Coupon{Active: true, MinOrder: 20000}, 19999, ErrCouponMinOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.coupon.Check(tt.total, now); err != tt.wantErr {
+				t.Errorf("Check() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
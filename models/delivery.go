@@ -0,0 +1,120 @@
+package model
+
+import "encoding/json"
+
+const (
+	DeliveryZoneRadius  = "radius"
+	DeliveryZonePolygon = "polygon"
+)
+
+// DeliveryZone is an area a restaurant delivers to. Radius zones are centred on
+// the restaurant's coordinates; polygon zones hold a GeoJSON Polygon geometry.
+type DeliveryZone struct {
+	ID           string  `gorm:"column:id;size:100" json:"id"`
+	RestaurantID string  `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	Name         string  `gorm:"column:name" json:"name"`
+	ZoneType     string  `gorm:"column:zone_type;size:20" json:"zoneType"`
+	RadiusKm     float64 `gorm:"column:radius_km" json:"radiusKm"`
+	Polygon      string  `gorm:"column:polygon;type:text" json:"polygon"`
+	MinOrder     Amount  `gorm:"column:min_order_minor" json:"minOrder"`
+	DeliveryFee  Amount  `gorm:"column:delivery_fee_minor" json:"deliveryFee"`
+}
+
+type geoJSONPolygon struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// Validate checks the zone definition, parsing the polygon when there is one.
+func (z *DeliveryZone) Validate() error {
+	if z.MinOrder < 0 || z.DeliveryFee < 0 {
+		return ErrInvalidDeliveryZone
+	}
+	switch z.ZoneType {
+	case DeliveryZoneRadius:
+		if z.RadiusKm <= 0 {
+			return ErrInvalidDeliveryZone
+		}
+		return nil
+	case DeliveryZonePolygon:
+		_, err := parsePolygon(z.Polygon)
+		return err
+	default:
+		return ErrInvalidDeliveryZone
+	}
+}
+
+// Contains reports whether (lat, lng) falls inside the zone. restaurant supplies
+// the centre for radius zones.
+func (z *DeliveryZone) Contains(restaurant *Restaurant, lat, lng float64) bool {
+	switch z.ZoneType {
+	case DeliveryZoneRadius:
+		return HaversineKm(restaurant.Latitude, restaurant.Longitude, lat, lng) <= z.RadiusKm
+	case DeliveryZonePolygon:
+		rings, err := parsePolygon(z.Polygon)
+		if err != nil {
+			return false
+		}
+		// The first ring is the outer boundary; any further rings are holes.
+		if !ringContains(rings[0], lat, lng) {
+			return false
+		}
+		for _, hole := range rings[1:] {
+			if ringContains(hole, lat, lng) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func parsePolygon(raw string) ([][][2]float64, error) {
+	var geometry geoJSONPolygon
+	if err := json.Unmarshal([]byte(raw), &geometry); err != nil {
+		return nil, ErrInvalidDeliveryZone
+	}
+	if geometry.Type != "Polygon" || len(geometry.Coordinates) == 0 {
+		return nil, ErrInvalidDeliveryZone
+	}
+	for _, ring := range geometry.Coordinates {
+		if len(ring) < 4 {
+			return nil, ErrInvalidDeliveryZone
+		}
+		for _, p := range ring {
+			if err := ValidateCoordinates(p[1], p[0]); err != nil {
+				return nil, ErrInvalidDeliveryZone
+			}
+		}
+	}
+	return geometry.Coordinates, nil
+}
+
+// ringContains is an even-odd ray cast. GeoJSON positions are [lng, lat].
+func ringContains(ring [][2]float64, lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) {
+			crossX := (xj-xi)*(lat-yi)/(yj-yi) + xi
+			if lng < crossX {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// CheapestZone picks the matching zone with the lowest delivery fee, so an
+// address covered by overlapping zones gets the best price.
+func CheapestZone(zones []*DeliveryZone) *DeliveryZone {
+	var best *DeliveryZone
+	for _, z := range zones {
+		if best == nil || z.DeliveryFee < best.DeliveryFee {
+			best = z
+		}
+	}
+	return best
+}
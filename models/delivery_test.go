@@ -0,0 +1,85 @@
+package model
+
+import "testing"
+
+func TestRingContains(t *testing.T) {
+	square := [][2]float64{{77, 12}, {78, 12}, {78, 13}, {77, 13}, {77, 12}}
+	// An L shape: the square with its top-right quarter cut away.
+	ell := [][2]float64{{77, 12}, {78, 12}, {78, 12.5}, {77.5, 12.5}, {77.5, 13}, {77, 13}, {77, 12}}
+
+	tests := []struct {
+		name     string
+		ring     [][2]float64
+		lat, lng float64
+		want     bool
+	}{
+		{"centre", square, 12.5, 77.5, true},
+		{"north of the ring", square, 13.5, 77.5, false},
+		{"west of the ring", square, 12.5, 76.5, false},
+		{"lat and lng are not swapped", square, 77.5, 12.5, false},
+		{"inside the concave part", ell, 12.25, 77.75, true},
+		{"in the cut-away corner", ell, 12.75, 77.75, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ringContains(tt.ring, tt.lat, tt.lng); got != tt.want {
+				t.Errorf("ringContains(%v, %v) = %v, want %v", tt.lat, tt.lng, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliveryZoneContains(t *testing.T) {
+	restaurant := &Restaurant{Latitude: 12.9716, Longitude: 77.5946}
+	withHole := `{"type":"Polygon","coordinates":[
+		[[77,12],[78,12],[78,13],[77,13],[77,12]],
+		[[77.4,12.4],[77.6,12.4],[77.6,12.6],[77.4,12.6],[77.4,12.4]]]}`
+
+	tests := []struct {
+		name     string
+		zone     DeliveryZone
+		lat, lng float64
+		want     bool
+	}{
+		{"radius inside", DeliveryZone{ZoneType: DeliveryZoneRadius, RadiusKm: 5}, 12.99, 77.60, true},
+		{"radius outside", DeliveryZone{ZoneType: DeliveryZoneRadius, RadiusKm: 5}, 13.10, 77.60, false},
+		{"polygon inside", DeliveryZone{ZoneType: DeliveryZonePolygon, Polygon: withHole}, 12.2, 77.2, true},
+		{"polygon hole", DeliveryZone{ZoneType: DeliveryZonePolygon, Polygon: withHole}, 12.5, 77.5, false},
+		{"polygon outside", DeliveryZone{ZoneType: DeliveryZonePolygon, Polygon: withHole}, 13.5, 77.5, false},
+		{"invalid polygon", DeliveryZone{ZoneType: DeliveryZonePolygon, Polygon: `{"type":"Point"}`}, 12.2, 77.2, false},
+		{"unknown type", DeliveryZone{ZoneType: "circle", RadiusKm: 5}, 12.97, 77.59, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.zone.Contains(restaurant, tt.lat, tt.lng); got != tt.want {
+				t.Errorf("Contains(%v, %v) = %v, want %v", tt.lat, tt.lng, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheapestZone(t *testing.T) {
+	near := &DeliveryZone{ID: "near", DeliveryFee: 2000}
+	far := &DeliveryZone{ID: "far", DeliveryFee: 5000}
+	free := &DeliveryZone{ID: "free", DeliveryFee: 0}
+	tie := &DeliveryZone{ID: "tie", DeliveryFee: 2000}
+
+	tests := []struct {
+		name  string
+		zones []*DeliveryZone
+		want  *DeliveryZone
+	}{
+		{"no zones", nil, nil},
+		{"single zone", []*DeliveryZone{far}, far},
+		{"lowest fee wins", []*DeliveryZone{far, near}, near},
+		{"free delivery", []*DeliveryZone{near, free, far}, free},
+		{"first of equal fees", []*DeliveryZone{near, tie}, near},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheapestZone(tt.zones); got != tt.want {
+				t.Errorf("CheapestZone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,11 +3,77 @@ package model
 import "errors"
 
 var (
-    ErrRestaurantNotFound     = errors.New("restaurant not found")
-    ErrProductNotFound        = errors.New("product not found")
-    ErrInvalidCredentials     = errors.New("invalid credentials")
-    ErrEmailAlreadyExists     = errors.New("email already exists")
-    ErrRestaurantIsBanned     = errors.New("restaurant is banned")
-    ErrInsufficientStock      = errors.New("insufficient stock")
-    ErrInvalidStockOperation  = errors.New("invalid stock operation")
-)
\ No newline at end of file
+	ErrRestaurantNotFound      = errors.New("restaurant not found")
+	ErrProductNotFound         = errors.New("product not found")
+	ErrInvalidCredentials      = errors.New("invalid credentials")
+	ErrActorRequired           = errors.New("authenticated caller is required")
+	ErrEmailAlreadyExists      = errors.New("email already exists")
+	ErrRestaurantIsBanned      = errors.New("restaurant is banned")
+	ErrInsufficientStock       = errors.New("insufficient stock")
+	ErrInvalidStockOperation   = errors.New("invalid stock operation")
+	ErrStockNotEditable        = errors.New("stock cannot be edited; use the stock increment and decrement calls")
+	ErrInvalidOpeningHours     = errors.New("invalid opening hours")
+	ErrInvalidHolidayDate      = errors.New("invalid holiday date")
+	ErrInvalidTimezone         = errors.New("invalid timezone")
+	ErrInvalidCoordinates      = errors.New("invalid coordinates")
+	ErrInvalidSearchRadius     = errors.New("invalid search radius")
+	ErrInvalidDeliveryZone     = errors.New("invalid delivery zone")
+	ErrDeliveryZoneNotFound    = errors.New("delivery zone not found")
+	ErrAddressNotServiceable   = errors.New("address is outside all delivery zones")
+	ErrBrandNotFound           = errors.New("brand not found")
+	ErrBranchNotInBrand        = errors.New("restaurant is not a branch of this brand")
+	ErrAlreadyInBrand          = errors.New("restaurant already belongs to a brand")
+	ErrInvalidStatusChange     = errors.New("invalid restaurant status transition")
+	ErrInvalidBanCategory      = errors.New("invalid ban category")
+	ErrInvalidBanDuration      = errors.New("invalid ban duration")
+	ErrBanNotFound             = errors.New("ban not found")
+	ErrBanNotActive            = errors.New("ban is no longer active")
+	ErrAppealNotFound          = errors.New("appeal not found")
+	ErrAppealAlreadyPending    = errors.New("an appeal for this ban is already pending")
+	ErrAppealAlreadyReviewed   = errors.New("appeal has already been reviewed")
+	ErrInvalidAppealToken      = errors.New("invalid or expired appeal token")
+	ErrRestaurantNotBanned     = errors.New("restaurant is not banned")
+	ErrInvalidDocument         = errors.New("invalid KYC document")
+	ErrDocumentNotFound        = errors.New("KYC document not found")
+	ErrDocumentReviewed        = errors.New("KYC document has already been reviewed")
+	ErrInvalidRating           = errors.New("rating must be between 1 and 5")
+	ErrInvalidReview           = errors.New("invalid review")
+	ErrReviewNotFound          = errors.New("review not found")
+	ErrDuplicateReview         = errors.New("order has already been reviewed")
+	ErrUnknownTag              = errors.New("unknown tag")
+	ErrEmptySearchQuery        = errors.New("search query is empty")
+	ErrInvalidPageToken        = errors.New("invalid page token")
+	ErrInvalidPageSize         = errors.New("invalid page size")
+	ErrInvalidSort             = errors.New("invalid sort order")
+	ErrInvalidPriceRange       = errors.New("invalid price range")
+	ErrInvalidCurrency         = errors.New("unsupported currency")
+	ErrInvalidAmount           = errors.New("invalid amount")
+	ErrCurrencyChange          = errors.New("currency cannot change while products exist")
+	ErrTaxClassNotFound        = errors.New("tax class not found")
+	ErrInvalidTaxRate          = errors.New("invalid tax rate")
+	ErrInvalidLineItem         = errors.New("invalid line item")
+	ErrInvalidPromotion        = errors.New("invalid promotion")
+	ErrPromotionNotFound       = errors.New("promotion not found")
+	ErrPromotionLimitReached   = errors.New("promotion already used the maximum number of times by this customer")
+	ErrPromotionNeedsCustomer  = errors.New("customer is required for promotions limited per customer")
+	ErrPromotionNeedsOrder     = errors.New("order is required to record promotion usage")
+	ErrInvalidCoupon           = errors.New("invalid coupon")
+	ErrCouponNotFound          = errors.New("coupon not found")
+	ErrCouponInactive          = errors.New("coupon is not active")
+	ErrCouponExpired           = errors.New("coupon has expired")
+	ErrCouponMinOrder          = errors.New("order total is below the coupon minimum")
+	ErrCouponExhausted         = errors.New("coupon usage limit reached")
+	ErrCouponUserLimit         = errors.New("coupon already used the maximum number of times by this customer")
+	ErrCouponAlreadyRedeemed   = errors.New("a coupon has already been redeemed for this order")
+	ErrInvalidPriceSchedule    = errors.New("invalid price schedule")
+	ErrPriceScheduleNotFound   = errors.New("price schedule not found")
+	ErrProductVersionNotFound  = errors.New("product version not found")
+	ErrProductNotDeleted       = errors.New("product is not deleted")
+	ErrEmptyUpdate             = errors.New("no fields to update")
+	ErrInvalidProductUpdate    = errors.New("invalid product update")
+	ErrInvalidRestaurantUpdate = errors.New("invalid restaurant update")
+	ErrProductOwnership        = errors.New("product belongs to another restaurant")
+	ErrVersionRequired         = errors.New("expected version is required")
+	ErrStaleVersion            = errors.New("record was changed by someone else; reload and retry")
+	ErrInvalidImportFormat     = errors.New("invalid import file")
+)
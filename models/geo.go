@@ -0,0 +1,62 @@
+package model
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// NearbyRestaurant pairs a restaurant with its distance from a search point.
+type NearbyRestaurant struct {
+	Restaurant *Restaurant `json:"restaurant"`
+	DistanceKm float64     `json:"distanceKm"`
+}
+
+// BoundingBox is a latitude/longitude rectangle used to prefilter candidates
+// with plain indexed comparisons before computing exact distances.
+type BoundingBox struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// ValidateCoordinates checks that lat/lng are within the WGS84 range.
+func ValidateCoordinates(lat, lng float64) error {
+	if math.IsNaN(lat) || math.IsNaN(lng) || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// HaversineKm returns the great-circle distance between two points in kilometres.
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBoxAround returns a box that contains every point within radiusKm of
+// (lat, lng). It is widened to the full longitude range near the poles or when
+// the box would cross the antimeridian.
+func BoundingBoxAround(lat, lng, radiusKm float64) BoundingBox {
+	dLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	box := BoundingBox{
+		MinLat: math.Max(-90, lat-dLat),
+		MaxLat: math.Min(90, lat+dLat),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+
+	cosLat := math.Cos(toRadians(lat))
+	if box.MinLat > -90 && box.MaxLat < 90 && cosLat > 0 {
+		dLng := dLat / cosLat
+		if lng-dLng >= -180 && lng+dLng <= 180 {
+			box.MinLng = lng - dLng
+			box.MaxLng = lng + dLng
+		}
+	}
+	return box
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
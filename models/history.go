@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// ProductVersion is a snapshot of a product's editable fields after a change.
+// Versions are numbered from 1 per product; version 1 is the product as it was
+// before its first recorded edit.
+type ProductVersion struct {
+	ID          uint      `gorm:"column:id;primaryKey" json:"id"`
+	ProductID   string    `gorm:"column:product_id;size:50;uniqueIndex:idx_product_version" json:"productId"`
+	Version     int       `gorm:"column:version;uniqueIndex:idx_product_version" json:"version"`
+	Name        string    `gorm:"column:name" json:"name"`
+	Description string    `gorm:"column:description" json:"description"`
+	Price       Amount    `gorm:"column:price_minor" json:"price"`
+	Stock       int32     `gorm:"column:stock" json:"stock"`
+	Category    string    `gorm:"column:category" json:"category"`
+	ActorID     string    `gorm:"column:actor_id;size:100" json:"actorId"`
+	Note        string    `gorm:"column:note" json:"note"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"createdAt"`
+}
+
+// NewProductVersion snapshots the product's current fields.
+func NewProductVersion(p *Product, actorID, note string) *ProductVersion {
+	return &ProductVersion{
+		ProductID:   p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       p.Price,
+		Stock:       p.Stock,
+		Category:    p.Category,
+		ActorID:     actorID,
+		Note:        note,
+	}
+}
+
+// RestoreTo copies the snapshot's listing fields back onto the product. Stock is
+// left alone: it tracks inventory, which moves with orders rather than edits.
+func (v *ProductVersion) RestoreTo(p *Product) {
+	p.Name = v.Name
+	p.Description = v.Description
+	p.Price = v.Price
+	p.Category = v.Category
+}
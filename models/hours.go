@@ -0,0 +1,135 @@
+package model
+
+import "time"
+
+// DefaultTimezone is used for restaurants that have not set their own timezone.
+const DefaultTimezone = "Asia/Kolkata"
+
+// openStatusLookaheadDays bounds how far ahead the next opening time is searched,
+// so a long holiday closure still yields a result without scanning forever.
+const openStatusLookaheadDays = 60
+
+// OpeningHours is a single shift on a weekday. A restaurant with split shifts has
+// several rows for the same weekday. Times are minutes since midnight in the
+// restaurant's timezone; a shift whose ClosesAt is not after OpensAt runs past midnight.
+type OpeningHours struct {
+	ID           uint   `gorm:"column:id;primaryKey" json:"id"`
+	RestaurantID string `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	Weekday      int    `gorm:"column:weekday" json:"weekday"`
+	OpensAt      int    `gorm:"column:opens_at" json:"opensAt"`
+	ClosesAt     int    `gorm:"column:closes_at" json:"closesAt"`
+}
+
+// HolidayClosure closes a restaurant for a whole calendar day (YYYY-MM-DD in the
+// restaurant's timezone). Shifts starting on that day are skipped.
+type HolidayClosure struct {
+	ID           uint   `gorm:"column:id;primaryKey" json:"id"`
+	RestaurantID string `gorm:"column:restaurant_id;size:100;uniqueIndex:idx_holiday_restaurant_date" json:"restaurantId"`
+	Date         string `gorm:"column:date;size:10;uniqueIndex:idx_holiday_restaurant_date" json:"date"`
+	Reason       string `gorm:"column:reason" json:"reason"`
+}
+
+// OpenStatus is the computed availability of a restaurant at a point in time.
+// NextOpenAt is zero when the restaurant is open now, paused, or has no upcoming shift.
+type OpenStatus struct {
+	IsOpenNow  bool      `json:"isOpenNow"`
+	IsPaused   bool      `json:"isPaused"`
+	NextOpenAt time.Time `json:"nextOpenAt"`
+}
+
+// Validate checks that the shift refers to a real weekday and minute range.
+func (h *OpeningHours) Validate() error {
+	if h.Weekday < int(time.Sunday) || h.Weekday > int(time.Saturday) {
+		return ErrInvalidOpeningHours
+	}
+	if h.OpensAt < 0 || h.OpensAt >= 24*60 || h.ClosesAt < 0 || h.ClosesAt > 24*60 {
+		return ErrInvalidOpeningHours
+	}
+	if h.OpensAt == h.ClosesAt {
+		return ErrInvalidOpeningHours
+	}
+	return nil
+}
+
+// ValidateTimezone checks that name is an IANA timezone such as "Asia/Kolkata".
+func ValidateTimezone(name string) error {
+	if name == "" || name == "Local" {
+		return ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ErrInvalidTimezone
+	}
+	return nil
+}
+
+// Location returns the restaurant's timezone, falling back to DefaultTimezone.
+// Timezones are validated when they are set, so a name that does not load can
+// only come from a row edited by hand and is treated as unset.
+func (r *Restaurant) Location() *time.Location {
+	if r.Timezone != "" {
+		if loc, err := time.LoadLocation(r.Timezone); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ComputeOpenStatus works out whether the restaurant is open at now and, if not,
+// when it next opens according to its weekly hours and holiday closures.
+func ComputeOpenStatus(r *Restaurant, hours []*OpeningHours, closures []*HolidayClosure, now time.Time) OpenStatus {
+	if r.IsPaused {
+		return OpenStatus{IsPaused: true}
+	}
+
+	loc := r.Location()
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	closed := make(map[string]bool, len(closures))
+	for _, c := range closures {
+		closed[c.Date] = true
+	}
+
+	var next time.Time
+	// Start from yesterday so an overnight shift that began then is still considered.
+	for offset := -1; offset <= openStatusLookaheadDays; offset++ {
+		day := today.AddDate(0, 0, offset)
+		if closed[day.Format("2006-01-02")] {
+			continue
+		}
+		for _, h := range hours {
+			if h.Weekday != int(day.Weekday()) {
+				continue
+			}
+			start := atMinute(day, 0, h.OpensAt)
+			end := atMinute(day, 0, h.ClosesAt)
+			if h.ClosesAt <= h.OpensAt {
+				end = atMinute(day, 1, h.ClosesAt)
+			}
+			if !now.Before(start) && now.Before(end) {
+				return OpenStatus{IsOpenNow: true}
+			}
+			if start.After(now) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+		// Days are visited in order, so the first upcoming shift found is the earliest
+		// once the current day (which may still hold an open overnight shift) is done.
+		if !next.IsZero() && offset >= 0 {
+			break
+		}
+	}
+
+	return OpenStatus{NextOpenAt: next}
+}
+
+// atMinute is the wall-clock time minute minutes past midnight, days after day,
+// in day's location. Adding a duration to midnight instead would be an hour off
+// on the days clocks change.
+func atMinute(day time.Time, days, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+days, 0, minute, 0, 0, day.Location())
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // keep timezone cases independent of the host
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func everyDay(opensAt, closesAt int) []*OpeningHours {
+	hours := make([]*OpeningHours, 0, 7)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		hours = append(hours, &OpeningHours{Weekday: int(d), OpensAt: opensAt, ClosesAt: closesAt})
+	}
+	return hours
+}
+
+func TestComputeOpenStatus(t *testing.T) {
+	kolkata := mustLoad(t, DefaultTimezone)
+	london := mustLoad(t, "Europe/London")
+
+	// 2026-10-16 is a Friday
+	friday := func(h, m int) time.Time { return time.Date(2026, 10, 16, h, m, 0, 0, kolkata) }
+	lunch := []*OpeningHours{{Weekday: int(time.Friday), OpensAt: 11 * 60, ClosesAt: 15 * 60}}
+
+	tests := []struct {
+		name       string
+		restaurant *Restaurant
+		hours      []*OpeningHours
+		closures   []*HolidayClosure
+		now        time.Time
+		want       OpenStatus
+	}{
+		{
+			name:       "paused",
+			restaurant: &Restaurant{IsPaused: true},
+			hours:      lunch,
+			now:        friday(12, 0),
+			want:       OpenStatus{IsPaused: true},
+		},
+		{
+			name:       "inside a shift",
+			restaurant: &Restaurant{},
+			hours:      lunch,
+			now:        friday(12, 0),
+			want:       OpenStatus{IsOpenNow: true},
+		},
+		{
+			name:       "closing time is exclusive",
+			restaurant: &Restaurant{},
+			hours:      lunch,
+			now:        friday(15, 0),
+			want:       OpenStatus{NextOpenAt: time.Date(2026, 10, 23, 11, 0, 0, 0, kolkata)},
+		},
+		{
+			name:       "before opening",
+			restaurant: &Restaurant{},
+			hours:      lunch,
+			now:        friday(9, 30),
+			want:       OpenStatus{NextOpenAt: friday(11, 0)},
+		},
+		{
+			name:       "split shifts pick the next one",
+			restaurant: &Restaurant{},
+			hours: append(lunch[:1:1],
+				&OpeningHours{Weekday: int(time.Friday), OpensAt: 19 * 60, ClosesAt: 23 * 60}),
+			now:  friday(16, 0),
+			want: OpenStatus{NextOpenAt: friday(19, 0)},
+		},
+		{
+			name:       "overnight shift from the day before",
+			restaurant: &Restaurant{},
+			hours:      []*OpeningHours{{Weekday: int(time.Friday), OpensAt: 22 * 60, ClosesAt: 2 * 60}},
+			now:        time.Date(2026, 10, 17, 1, 30, 0, 0, kolkata),
+			want:       OpenStatus{IsOpenNow: true},
+		},
+		{
+			name:       "holiday skips the day",
+			restaurant: &Restaurant{},
+			hours:      everyDay(11*60, 15*60),
+			closures:   []*HolidayClosure{{Date: "2026-10-16"}},
+			now:        friday(12, 0),
+			want:       OpenStatus{NextOpenAt: time.Date(2026, 10, 17, 11, 0, 0, 0, kolkata)},
+		},
+		{
+			name:       "no hours",
+			restaurant: &Restaurant{},
+			now:        friday(12, 0),
+			want:       OpenStatus{},
+		},
+		{
+			name:       "restaurant timezone",
+			restaurant: &Restaurant{Timezone: "Europe/London"},
+			hours:      lunch,
+			// 12:00 in London is 16:30 in Kolkata, after the Kolkata lunch shift
+			now:  time.Date(2026, 10, 16, 12, 0, 0, 0, london),
+			want: OpenStatus{IsOpenNow: true},
+		},
+		{
+			// Clocks went forward at 01:00 on 2026-03-29, so midnight plus nine
+			// hours is 10:00 local, not the 09:00 opening
+			name:       "opening on a DST change day",
+			restaurant: &Restaurant{Timezone: "Europe/London"},
+			hours:      everyDay(9*60, 17*60),
+			now:        time.Date(2026, 3, 29, 9, 30, 0, 0, london),
+			want:       OpenStatus{IsOpenNow: true},
+		},
+		{
+			name:       "next opening after a DST change",
+			restaurant: &Restaurant{Timezone: "Europe/London"},
+			hours:      everyDay(9*60, 17*60),
+			now:        time.Date(2026, 3, 29, 7, 0, 0, 0, london),
+			want:       OpenStatus{NextOpenAt: time.Date(2026, 3, 29, 9, 0, 0, 0, london)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeOpenStatus(tt.restaurant, tt.hours, tt.closures, tt.now)
+			if got.IsOpenNow != tt.want.IsOpenNow || got.IsPaused != tt.want.IsPaused || !got.NextOpenAt.Equal(tt.want.NextOpenAt) {
+				t.Errorf("ComputeOpenStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"Asia/Kolkata", false},
+		{"Europe/London", false},
+		{"UTC", false},
+		{"", true},
+		{"Local", true},
+		{"Mars/Olympus_Mons", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateTimezone(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTimezone(%q) = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
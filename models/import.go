@@ -0,0 +1,316 @@
+package model
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Bulk import formats.
+const (
+	ImportCSV  = "csv"
+	ImportJSON = "json"
+)
+
+// importColumns are the CSV header names; name and price are required.
+var importColumns = []string{"sku", "name", "description", "price", "stock", "category"}
+
+// ImportRow is one product as written in an import file, before validation.
+// Line is the 1-based line the row starts on, header included for CSV.
+type ImportRow struct {
+	Line        int      `json:"-"`
+	SKU         string   `json:"sku"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Price       *float64 `json:"price"`
+	Stock       *int32   `json:"stock"`
+	Category    string   `json:"category"`
+
+	// unreadable lists the fields that could not be parsed; they have been
+	// reported already and the row is never imported.
+	unreadable []string
+}
+
+func (r *ImportRow) readable(field string) bool {
+	for _, f := range r.unreadable {
+		if f == field {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportedProduct is a validated import row with its price in minor units. A nil
+// Stock leaves the stock of an existing product alone and starts new ones at 0.
+type ImportedProduct struct {
+	Line        int
+	SKU         string
+	Name        string
+	Description string
+	Price       Amount
+	Stock       *int32
+	Category    string
+}
+
+// ImportError reports a problem with one line of an import file.
+type ImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ImportResult summarises a bulk import. Nothing is written when Errors is not
+// empty or DryRun is set; the counts then describe what the import would do.
+type ImportResult struct {
+	DryRun    bool          `json:"dryRun"`
+	Created   int           `json:"created"`
+	Updated   int           `json:"updated"`
+	Unchanged int           `json:"unchanged"`
+	Errors    []ImportError `json:"errors"`
+}
+
+// ParseImport reads rows in the given format. Values that cannot be read are
+// reported per line; their rows are still returned, marked so ValidateImportRows
+// reports the rest of their problems and never accepts them. Only a file whose
+// structure is broken, such as a CSV with unknown columns, fails as a whole.
+// Checking the values themselves is left to ValidateImportRows.
+func ParseImport(format string, data []byte) ([]*ImportRow, []ImportError, error) {
+	switch strings.ToLower(format) {
+	case ImportCSV:
+		return parseImportCSV(data)
+	case ImportJSON:
+		return parseImportJSON(data)
+	default:
+		return nil, nil, ErrInvalidImportFormat
+	}
+}
+
+func parseImportCSV(data []byte) ([]*ImportRow, []ImportError, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFormat, err)
+	}
+	// Like the JSON decoder, refuse columns that would otherwise be ignored
+	known := make(map[string]bool, len(importColumns))
+	for _, c := range importColumns {
+		known[c] = true
+	}
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		if !known[name] {
+			return nil, nil, fmt.Errorf("%w: unknown %q column", ErrInvalidImportFormat, h)
+		}
+		if _, dup := index[name]; dup {
+			return nil, nil, fmt.Errorf("%w: duplicate %q column", ErrInvalidImportFormat, h)
+		}
+		index[name] = i
+	}
+	for _, required := range []string{"name", "price"} {
+		if _, ok := index[required]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing %q column", ErrInvalidImportFormat, required)
+		}
+	}
+
+	var rows []*ImportRow
+	var problems []ImportError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFormat, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			i, ok := index[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := &ImportRow{
+			Line:        line,
+			SKU:         field("sku"),
+			Name:        field("name"),
+			Description: field("description"),
+			Category:    field("category"),
+		}
+		// Unreadable values are reported here and the row is still validated, so
+		// every problem on the line shows up at once
+		if v := field("price"); v != "" {
+			if price, err := strconv.ParseFloat(v, 64); err == nil {
+				row.Price = &price
+			} else {
+				problems = append(problems, ImportError{Line: line, Message: fmt.Sprintf("price %q is not a number", v)})
+				row.unreadable = append(row.unreadable, "price")
+			}
+		}
+		if v := field("stock"); v != "" {
+			if stock, err := strconv.ParseInt(v, 10, 32); err == nil {
+				s := int32(stock)
+				row.Stock = &s
+			} else {
+				problems = append(problems, ImportError{Line: line, Message: fmt.Sprintf("stock %q is not a whole number", v)})
+				row.unreadable = append(row.unreadable, "stock")
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, problems, nil
+}
+
+// parseImportJSON reads an array of objects, recording the line each object
+// starts on so errors can point at it. An object with a wrongly typed or unknown
+// field is reported and skipped; the decoder has consumed it whole by then.
+func parseImportJSON(data []byte) ([]*ImportRow, []ImportError, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('[') {
+		return nil, nil, fmt.Errorf("%w: expected a JSON array of products", ErrInvalidImportFormat)
+	}
+
+	var rows []*ImportRow
+	var problems []ImportError
+	for dec.More() {
+		line := lineAt(data, dec.InputOffset())
+		var row ImportRow
+		if err := dec.Decode(&row); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImportFormat, line, err)
+			}
+			problems = append(problems, ImportError{Line: line, Message: err.Error()})
+			continue
+		}
+		row.Line = line
+		rows = append(rows, &row)
+	}
+	return rows, problems, nil
+}
+
+// lineAt returns the 1-based line of the first non-separator byte at or after offset.
+func lineAt(data []byte, offset int64) int {
+	for int(offset) < len(data) && strings.ContainsRune(" \t\r\n,", rune(data[offset])) {
+		offset++
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// ValidateImportRows checks every row, converting prices with the restaurant's
+// currency, and reports all problems rather than stopping at the first. A SKU,
+// or a name for rows without one, may only appear once per file.
+func ValidateImportRows(rows []*ImportRow, currency string) ([]*ImportedProduct, []ImportError) {
+	var products []*ImportedProduct
+	var problems []ImportError
+	seen := make(map[string]int, len(rows))
+
+	for _, row := range rows {
+		valid := len(row.unreadable) == 0
+		fail := func(format string, args ...interface{}) {
+			problems = append(problems, ImportError{Line: row.Line, Message: fmt.Sprintf(format, args...)})
+			valid = false
+		}
+
+		name := strings.TrimSpace(row.Name)
+		if !validText(name, true) {
+			fail("name is required and must be at most %d characters", maxTextField)
+		}
+		if !validText(row.Category, false) || !validText(row.SKU, false) {
+			fail("sku and category must be at most %d characters", maxTextField)
+		}
+		if len(row.Description) > maxDescription {
+			fail("description must be at most %d characters", maxDescription)
+		}
+		var price Amount
+		if row.Price != nil {
+			var err error
+			price, err = ToMinor(*row.Price, currency)
+			if err != nil || price < 0 {
+				fail("price %v is not a valid amount", *row.Price)
+			}
+		} else if row.readable("price") {
+			fail("price is required")
+		}
+		if row.Stock != nil && *row.Stock < 0 {
+			fail("stock cannot be negative")
+		}
+		if !valid {
+			continue
+		}
+
+		key := ImportKey(row.SKU, name)
+		if first, dup := seen[key]; dup {
+			fail("duplicates the product on line %d", first)
+			continue
+		}
+		seen[key] = row.Line
+
+		products = append(products, &ImportedProduct{
+			Line:        row.Line,
+			SKU:         strings.TrimSpace(row.SKU),
+			Name:        name,
+			Description: row.Description,
+			Price:       price,
+			Stock:       row.Stock,
+			Category:    strings.TrimSpace(row.Category),
+		})
+	}
+	return products, problems
+}
+
+// ImportKey identifies the product a row upserts: its SKU when it has one,
+// otherwise its name, compared case-insensitively.
+func ImportKey(sku, name string) string {
+	if sku = strings.TrimSpace(sku); sku != "" {
+		return "sku:" + sku
+	}
+	return "name:" + strings.ToLower(strings.TrimSpace(name))
+}
+
+// SKUCode returns the product's SKU, or "" when it has none. Products without
+// one store NULL so the per-restaurant unique index does not apply to them.
+func (p *Product) SKUCode() string {
+	if p.SKU == nil {
+		return ""
+	}
+	return *p.SKU
+}
+
+// ApplyTo copies the imported fields onto an existing product. listing reports
+// whether a field kept in the product history changed, inventory whether the SKU
+// or stock did.
+func (ip *ImportedProduct) ApplyTo(p *Product) (listing, inventory bool) {
+	listing = p.Name != ip.Name || p.Description != ip.Description ||
+		p.Price != ip.Price || p.Category != ip.Category
+	inventory = (ip.SKU != "" && p.SKUCode() != ip.SKU) || (ip.Stock != nil && p.Stock != *ip.Stock)
+	p.Name = ip.Name
+	p.Description = ip.Description
+	p.Price = ip.Price
+	p.Category = ip.Category
+	if ip.SKU != "" {
+		sku := ip.SKU
+		p.SKU = &sku
+	}
+	if ip.Stock != nil {
+		p.Stock = *ip.Stock
+	}
+	return listing, inventory
+}
@@ -0,0 +1,227 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func price(v float64) *float64 { return &v }
+
+func stock(v int32) *int32 { return &v }
+
+func problemLines(problems []ImportError) []int {
+	var lines []int
+	for _, p := range problems {
+		lines = append(lines, p.Line)
+	}
+	return lines
+}
+
+func TestParseImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		data     string
+		want     []*ImportRow
+		problems []int // lines reported
+		wantErr  bool
+	}{
+		{
+			name:   "csv",
+			format: ImportCSV,
+			data:   "sku,name,description,price,stock,category\nB1,Burger,Double patty,199.50,10,mains\n,Fries,,99,,sides\n",
+			want: []*ImportRow{
+				{Line: 2, SKU: "B1", Name: "Burger", Description: "Double patty", Price: price(199.5), Stock: stock(10), Category: "mains"},
+				{Line: 3, Name: "Fries", Price: price(99), Category: "sides"},
+			},
+		},
+		{
+			name:   "csv columns in any order and case",
+			format: "CSV",
+			data:   "Price, Name\n20,Tea\n",
+			want:   []*ImportRow{{Line: 2, Name: "Tea", Price: price(20)}},
+		},
+		{
+			name:   "csv quoted field over several lines",
+			format: ImportCSV,
+			data:   "name,price,description\nCake,100,\"two\nlines\"\nTea,20,\n",
+			want: []*ImportRow{
+				{Line: 2, Name: "Cake", Price: price(100), Description: "two\nlines"},
+				{Line: 4, Name: "Tea", Price: price(20)},
+			},
+		},
+		{
+			name:     "csv unreadable values are reported and kept",
+			format:   ImportCSV,
+			data:     "name,price,stock\nBurger,cheap,lots\nTea,20,5\n",
+			problems: []int{2, 2},
+			want: []*ImportRow{
+				{Line: 2, Name: "Burger", unreadable: []string{"price", "stock"}},
+				{Line: 3, Name: "Tea", Price: price(20), Stock: stock(5)},
+			},
+		},
+		{name: "csv unknown column", format: ImportCSV, data: "name,price,colour\nTea,20,green\n", wantErr: true},
+		{name: "csv duplicate column", format: ImportCSV, data: "name,price,Name\nTea,20,Chai\n", wantErr: true},
+		{name: "csv missing price column", format: ImportCSV, data: "name,stock\nTea,5\n", wantErr: true},
+		{name: "csv empty file", format: ImportCSV, data: "", wantErr: true},
+		{
+			name:   "json",
+			format: ImportJSON,
+			data:   "[\n  {\"sku\": \"B1\", \"name\": \"Burger\", \"price\": 199.5, \"stock\": 3},\n  {\"name\": \"Tea\", \"price\": 20}\n]",
+			want: []*ImportRow{
+				{Line: 2, SKU: "B1", Name: "Burger", Price: price(199.5), Stock: stock(3)},
+				{Line: 3, Name: "Tea", Price: price(20)},
+			},
+		},
+		{
+			name:     "json bad objects are reported and skipped",
+			format:   ImportJSON,
+			data:     "[\n  {\"name\": \"Burger\", \"colour\": \"red\"},\n  {\"name\": \"Fries\", \"price\": \"cheap\"},\n  {\"name\": \"Tea\", \"price\": 20}\n]",
+			problems: []int{2, 3},
+			want:     []*ImportRow{{Line: 4, Name: "Tea", Price: price(20)}},
+		},
+		{name: "json not an array", format: ImportJSON, data: `{"name": "Tea", "price": 20}`, wantErr: true},
+		{name: "json truncated", format: ImportJSON, data: `[{"name": "Tea", "price": 20}, {"name": `, wantErr: true},
+		{name: "unknown format", format: "xml", data: "<products/>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, problems, err := ParseImport(tt.format, []byte(tt.data))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidImportFormat) {
+					t.Fatalf("ParseImport() error = %v, want ErrInvalidImportFormat", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseImport() error = %v", err)
+			}
+			if got := problemLines(problems); !reflect.DeepEqual(got, tt.problems) {
+				t.Errorf("problems on lines %v, want %v: %v", got, tt.problems, problems)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				for i := range rows {
+					t.Logf("row %d: %+v", i, *rows[i])
+				}
+				t.Errorf("ParseImport() returned %d rows, want %d as listed", len(rows), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestValidateImportRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		rows     []*ImportRow
+		want     []*ImportedProduct
+		problems []int // lines reported
+	}{
+		{
+			name: "valid rows are trimmed and priced in minor units",
+			rows: []*ImportRow{
+				{Line: 2, SKU: " B1 ", Name: " Burger ", Price: price(199.5), Stock: stock(10), Category: " mains "},
+				{Line: 3, Name: "Tea", Price: price(20)},
+			},
+			want: []*ImportedProduct{
+				{Line: 2, SKU: "B1", Name: "Burger", Price: 19950, Stock: stock(10), Category: "mains"},
+				{Line: 3, Name: "Tea", Price: 2000},
+			},
+		},
+		{
+			name:     "currency without minor units",
+			currency: "JPY",
+			rows:     []*ImportRow{{Line: 2, Name: "Ramen", Price: price(850)}},
+			want:     []*ImportedProduct{{Line: 2, Name: "Ramen", Price: 850}},
+		},
+		{
+			name:     "currency with three decimals",
+			currency: "KWD",
+			rows:     []*ImportRow{{Line: 2, Name: "Machboos", Price: price(1.25)}},
+			want:     []*ImportedProduct{{Line: 2, Name: "Machboos", Price: 1250}},
+		},
+		{
+			name:     "every problem on a row is reported",
+			rows:     []*ImportRow{{Line: 2, Name: " ", Stock: stock(-1)}},
+			problems: []int{2, 2, 2},
+		},
+		{
+			name:     "negative price",
+			rows:     []*ImportRow{{Line: 2, Name: "Tea", Price: price(-1)}},
+			problems: []int{2},
+		},
+		{
+			name:     "unknown currency",
+			currency: "XYZ",
+			rows:     []*ImportRow{{Line: 2, Name: "Tea", Price: price(20)}},
+			problems: []int{2},
+		},
+		{
+			name: "text limits",
+			rows: []*ImportRow{
+				{Line: 2, Name: strings.Repeat("n", maxTextField+1), Price: price(20)},
+				{Line: 3, Name: "Tea", Category: strings.Repeat("c", maxTextField+1), Price: price(20)},
+				{Line: 4, Name: "Cake", Description: strings.Repeat("d", maxDescription+1), Price: price(20)},
+			},
+			problems: []int{2, 3, 4},
+		},
+		{
+			// The price was already reported by ParseImport, so only the name is.
+			name:     "unreadable fields are not reported again",
+			rows:     []*ImportRow{{Line: 2, unreadable: []string{"price"}}},
+			problems: []int{2},
+		},
+		{
+			name:     "unreadable rows are never accepted",
+			rows:     []*ImportRow{{Line: 2, Name: "Tea", Price: price(20), unreadable: []string{"stock"}}},
+			problems: nil,
+		},
+		{
+			name: "duplicate SKU",
+			rows: []*ImportRow{
+				{Line: 2, SKU: "B1", Name: "Burger", Price: price(100)},
+				{Line: 3, SKU: "B1", Name: "Cheeseburger", Price: price(120)},
+			},
+			want:     []*ImportedProduct{{Line: 2, SKU: "B1", Name: "Burger", Price: 10000}},
+			problems: []int{3},
+		},
+		{
+			name: "duplicate name without SKU ignores case",
+			rows: []*ImportRow{
+				{Line: 2, Name: "Tea", Price: price(20)},
+				{Line: 3, Name: "TEA ", Price: price(25)},
+			},
+			want:     []*ImportedProduct{{Line: 2, Name: "Tea", Price: 2000}},
+			problems: []int{3},
+		},
+		{
+			name: "same name under different SKUs",
+			rows: []*ImportRow{
+				{Line: 2, SKU: "T1", Name: "Tea", Price: price(20)},
+				{Line: 3, SKU: "T2", Name: "Tea", Price: price(25)},
+			},
+			want: []*ImportedProduct{
+				{Line: 2, SKU: "T1", Name: "Tea", Price: 2000},
+				{Line: 3, SKU: "T2", Name: "Tea", Price: 2500},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, problems := ValidateImportRows(tt.rows, tt.currency)
+			if got := problemLines(problems); !reflect.DeepEqual(got, tt.problems) {
+				t.Errorf("problems on lines %v, want %v: %v", got, tt.problems, problems)
+			}
+			if !reflect.DeepEqual(products, tt.want) {
+				for i := range products {
+					t.Logf("product %d: %+v", i, *products[i])
+				}
+				t.Errorf("ValidateImportRows() accepted %d products, want %d as listed", len(products), len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// KYC document types.
+const (
+	DocFoodSafetyLicense = "food_safety_license"
+	DocTaxID             = "tax_id"
+	DocBankProof         = "bank_proof"
+)
+
+// KYC document states.
+const (
+	DocPending  = "pending"
+	DocVerified = "verified"
+	DocRejected = "rejected"
+	DocExpired  = "expired"
+)
+
+var kycDocumentTypes = map[string]bool{
+	DocFoodSafetyLicense: true,
+	DocTaxID:             true,
+	DocBankProof:         true,
+}
+
+// ValidDocumentType reports whether docType is a known KYC document type.
+func ValidDocumentType(docType string) bool {
+	return kycDocumentTypes[docType]
+}
+
+// KYCDocument is the metadata of a compliance document uploaded by a restaurant.
+// The file itself lives in object storage under StorageRef.
+type KYCDocument struct {
+	ID               string     `gorm:"column:id;size:100" json:"id"`
+	RestaurantID     string     `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	DocType          string     `gorm:"column:doc_type;size:50" json:"docType"`
+	Number           string     `gorm:"column:number" json:"number"`
+	ExpiresAt        *time.Time `gorm:"column:expires_at;index" json:"expiresAt"`
+	StorageRef       string     `gorm:"column:storage_ref" json:"storageRef"`
+	Status           string     `gorm:"column:status;size:20;index" json:"status"`
+	ReviewerID       string     `gorm:"column:reviewer_id;size:100" json:"reviewerId"`
+	ReviewComment    string     `gorm:"column:review_comment;type:text" json:"reviewComment"`
+	CreatedAt        time.Time  `gorm:"column:created_at" json:"createdAt"`
+	ReviewedAt       *time.Time `gorm:"column:reviewed_at" json:"reviewedAt"`
+	ExpiryNotifiedAt *time.Time `gorm:"column:expiry_notified_at" json:"expiryNotifiedAt"`
+}
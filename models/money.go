@@ -0,0 +1,68 @@
+package model
+
+import "math"
+
+// DefaultCurrency is the currency of restaurants that have not chosen one.
+const DefaultCurrency = "INR"
+
+// Amount is a monetary value in the minor unit of its currency (paise for INR),
+// so totals and taxes add up exactly.
+type Amount int64
+
+// currencyExponents is the number of minor-unit digits per ISO 4217 code.
+var currencyExponents = map[string]int{
+	"INR": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"AED": 2,
+	"SGD": 2,
+	"JPY": 0,
+	"KWD": 3,
+	"BHD": 3,
+	"OMR": 3,
+}
+
+// CurrencyExponent returns the minor-unit digits of a currency code.
+func CurrencyExponent(currency string) (int, error) {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	exp, ok := currencyExponents[currency]
+	if !ok {
+		return 0, ErrInvalidCurrency
+	}
+	return exp, nil
+}
+
+// ToMinor converts a major-unit value, as carried by the protobuf doubles, into
+// an Amount, rounding half away from zero to the currency's precision.
+func ToMinor(value float64, currency string) (Amount, error) {
+	exp, err := CurrencyExponent(currency)
+	if err != nil {
+		return 0, err
+	}
+	minor := math.Round(value * math.Pow10(exp))
+	if math.IsNaN(minor) || math.IsInf(minor, 0) || math.Abs(minor) >= math.MaxInt64 {
+		return 0, ErrInvalidAmount
+	}
+	return Amount(minor), nil
+}
+
+// Major converts the amount back into major units for the protobuf boundary.
+// Unknown currencies are treated like the default one.
+func (a Amount) Major(currency string) float64 {
+	exp, err := CurrencyExponent(currency)
+	if err != nil {
+		exp = currencyExponents[DefaultCurrency]
+	}
+	return float64(a) / math.Pow10(exp)
+}
+
+// CurrencyCode returns the restaurant's currency, falling back to DefaultCurrency.
+func (r *Restaurant) CurrencyCode() string {
+	if r.Currency == "" {
+		return DefaultCurrency
+	}
+	return r.Currency
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Sort orders for list queries.
+const (
+	SortName      = "name"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortNewest    = "newest"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ProductListOptions pages, sorts and filters product listings. Zero values mean
+// "no filter"; RestaurantID narrows the list to one restaurant, which is listed
+// whatever its review status as long as it is not banned.
+//
+// MinPrice, MaxPrice and the price sorts work on the listed price. Price
+// schedules depend on the time and the restaurant's timezone, so they cannot be
+// evaluated in the query: during a happy hour a product may be returned with an
+// effective price outside the range, or out of order.
+type ProductListOptions struct {
+	PageSize     int
+	PageToken    string
+	Sort         string
+	RestaurantID string
+	Category     string
+	MinPrice     Amount
+	MaxPrice     Amount
+	InStockOnly  bool
+	Pincode      string
+	Locality     string
+}
+
+// RestaurantListOptions pages, sorts and filters restaurant listings.
+type RestaurantListOptions struct {
+	PageSize  int
+	PageToken string
+	Sort      string
+	Pincode   string
+	Locality  string
+}
+
+// ProductPage is one page of products. NextPageToken is empty on the last page.
+type ProductPage struct {
+	Products      []*Product `json:"products"`
+	NextPageToken string     `json:"nextPageToken"`
+}
+
+// RestaurantPage is one page of restaurants. NextPageToken is empty on the last page.
+type RestaurantPage struct {
+	Restaurants   []*Restaurant `json:"restaurants"`
+	NextPageToken string        `json:"nextPageToken"`
+}
+
+// RestaurantWithProducts is a restaurant together with its menu.
+type RestaurantWithProducts struct {
+	Restaurant *Restaurant `json:"restaurant"`
+	Products   []*Product  `json:"products"`
+}
+
+// PageCursor is the position after the last row of a page: the sort key and ID
+// of that row. Clients only ever see it as an opaque token.
+type PageCursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"i"`
+}
+
+// EncodePageToken serialises a cursor into an opaque page token.
+func EncodePageToken(c PageCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodePageToken parses a page token. A token issued for a different sort order
+// is rejected, since its key would not mean anything for the new order.
+func DecodePageToken(token, sort string) (*PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var c PageCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" || c.Sort != sort {
+		return nil, ErrInvalidPageToken
+	}
+	return &c, nil
+}
+
+// NormalizePageSize clamps a requested page size to [1, MaxPageSize].
+func NormalizePageSize(size int) int {
+	if size <= 0 {
+		return DefaultPageSize
+	}
+	if size > MaxPageSize {
+		return MaxPageSize
+	}
+	return size
+}
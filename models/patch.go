@@ -0,0 +1,158 @@
+package model
+
+import "strings"
+
+// maxTextField is the longest name or category accepted from an edit.
+const maxTextField = 255
+
+// maxDescription is the longest product description accepted from an edit.
+const maxDescription = 2000
+
+// ProductPatch is a partial product edit: only non-nil fields change. Stock is
+// not editable here, as it moves through the stock RPCs, and neither is the
+// owning restaurant.
+type ProductPatch struct {
+	Name        *string
+	Description *string
+	Price       *Amount
+	Category    *string
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (p *ProductPatch) IsEmpty() bool {
+	return p.Name == nil && p.Description == nil && p.Price == nil && p.Category == nil
+}
+
+// Validate checks every field that is set.
+func (p *ProductPatch) Validate() error {
+	if p.IsEmpty() {
+		return ErrEmptyUpdate
+	}
+	if p.Name != nil && !validText(*p.Name, true) {
+		return ErrInvalidProductUpdate
+	}
+	if p.Category != nil && !validText(*p.Category, false) {
+		return ErrInvalidProductUpdate
+	}
+	if p.Description != nil && len(*p.Description) > maxDescription {
+		return ErrInvalidProductUpdate
+	}
+	if p.Price != nil && *p.Price < 0 {
+		return ErrInvalidAmount
+	}
+	return nil
+}
+
+// ApplyTo copies the set fields onto the product.
+func (p *ProductPatch) ApplyTo(product *Product) {
+	if p.Name != nil {
+		product.Name = strings.TrimSpace(*p.Name)
+	}
+	if p.Description != nil {
+		product.Description = *p.Description
+	}
+	if p.Price != nil {
+		product.Price = *p.Price
+	}
+	if p.Category != nil {
+		product.Category = strings.TrimSpace(*p.Category)
+	}
+}
+
+// RestaurantPatch is a partial edit of a restaurant's profile: only non-nil
+// fields change.
+type RestaurantPatch struct {
+	Name        *string
+	PhoneNumber *uint64
+	StreetName  *string
+	Locality    *string
+	State       *string
+	Pincode     *string
+	Latitude    *float64
+	Longitude   *float64
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (p *RestaurantPatch) IsEmpty() bool {
+	return p.Name == nil && p.PhoneNumber == nil && p.StreetName == nil &&
+		p.Locality == nil && p.State == nil && p.Pincode == nil &&
+		p.Latitude == nil && p.Longitude == nil
+}
+
+// Validate checks every field that is set. Pincodes are six-digit Indian PIN
+// codes, and coordinates are only ever set as a pair.
+func (p *RestaurantPatch) Validate() error {
+	if p.IsEmpty() {
+		return ErrEmptyUpdate
+	}
+	if p.Name != nil && !validText(*p.Name, true) {
+		return ErrInvalidRestaurantUpdate
+	}
+	if p.PhoneNumber != nil && *p.PhoneNumber == 0 {
+		return ErrInvalidRestaurantUpdate
+	}
+	for _, f := range []*string{p.StreetName, p.Locality, p.State} {
+		if f != nil && !validText(*f, true) {
+			return ErrInvalidRestaurantUpdate
+		}
+	}
+	if p.Pincode != nil && !validPincode(*p.Pincode) {
+		return ErrInvalidRestaurantUpdate
+	}
+	if (p.Latitude == nil) != (p.Longitude == nil) {
+		return ErrInvalidCoordinates
+	}
+	if p.Latitude != nil {
+		if err := ValidateCoordinates(*p.Latitude, *p.Longitude); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyTo copies the set fields onto the restaurant.
+func (p *RestaurantPatch) ApplyTo(r *Restaurant) {
+	if p.Name != nil {
+		r.Name = strings.TrimSpace(*p.Name)
+	}
+	if p.PhoneNumber != nil {
+		r.PhoneNumber = *p.PhoneNumber
+	}
+	if p.StreetName != nil {
+		r.StreetName = strings.TrimSpace(*p.StreetName)
+	}
+	if p.Locality != nil {
+		r.Locality = strings.TrimSpace(*p.Locality)
+	}
+	if p.State != nil {
+		r.State = strings.TrimSpace(*p.State)
+	}
+	if p.Pincode != nil {
+		r.Pincode = strings.TrimSpace(*p.Pincode)
+	}
+	if p.Latitude != nil && p.Longitude != nil {
+		r.Latitude = *p.Latitude
+		r.Longitude = *p.Longitude
+	}
+}
+
+func validText(s string, required bool) bool {
+	s = strings.TrimSpace(s)
+	if required && s == "" {
+		return false
+	}
+	return len(s) <= maxTextField
+}
+
+func validPincode(s string) bool {
+	s = strings.TrimSpace(s)
+	if len(s) != 6 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestProductPatchValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   ProductPatch
+		wantErr error
+	}{
+		{"empty", ProductPatch{}, ErrEmptyUpdate},
+		{"name", ProductPatch{Name: ptr("Masala Dosa")}, nil},
+		{"blank name", ProductPatch{Name: ptr("   ")}, ErrInvalidProductUpdate},
+		{"long name", ProductPatch{Name: ptr(strings.Repeat("n", maxTextField+1))}, ErrInvalidProductUpdate},
+		{"blank category clears it", ProductPatch{Category: ptr("")}, nil},
+		{"long category", ProductPatch{Category: ptr(strings.Repeat("c", maxTextField+1))}, ErrInvalidProductUpdate},
+		{"empty description", ProductPatch{Description: ptr("")}, nil},
+		{"long description", ProductPatch{Description: ptr(strings.Repeat("d", maxDescription+1))}, ErrInvalidProductUpdate},
+		{"free item", ProductPatch{Price: ptr(Amount(0))}, nil},
+		{"negative price", ProductPatch{Price: ptr(Amount(-1))}, ErrInvalidAmount},
+		{"one bad field fails the patch", ProductPatch{Name: ptr("Tea"), Price: ptr(Amount(-100))}, ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.patch.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProductPatchApplyTo(t *testing.T) {
+	product := &Product{Name: "Tea", Description: "Hot", Price: 2000, Category: "drinks", Stock: 7}
+	patch := ProductPatch{Name: ptr("  Masala Chai "), Price: ptr(Amount(2500))}
+	patch.ApplyTo(product)
+
+	want := Product{Name: "Masala Chai", Description: "Hot", Price: 2500, Category: "drinks", Stock: 7}
+	if product.Name != want.Name || product.Description != want.Description || product.Price != want.Price ||
+		product.Category != want.Category || product.Stock != want.Stock {
+		t.Errorf("ApplyTo() = %+v, want %+v", *product, want)
+	}
+}
+
+func TestRestaurantPatchValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   RestaurantPatch
+		wantErr error
+	}{
+		{"empty", RestaurantPatch{}, ErrEmptyUpdate},
+		{"name and phone", RestaurantPatch{Name: ptr("Dosa Corner"), PhoneNumber: ptr(uint64(9876543210))}, nil},
+		{"blank name", RestaurantPatch{Name: ptr(" ")}, ErrInvalidRestaurantUpdate},
+		{"zero phone", RestaurantPatch{PhoneNumber: ptr(uint64(0))}, ErrInvalidRestaurantUpdate},
+		{"blank locality", RestaurantPatch{Locality: ptr("")}, ErrInvalidRestaurantUpdate},
+		{"long state", RestaurantPatch{State: ptr(strings.Repeat("s", maxTextField+1))}, ErrInvalidRestaurantUpdate},
+		{"pincode", RestaurantPatch{Pincode: ptr(" 560001 ")}, nil},
+		{"short pincode", RestaurantPatch{Pincode: ptr("56001")}, ErrInvalidRestaurantUpdate},
+		{"pincode with letters", RestaurantPatch{Pincode: ptr("56000A")}, ErrInvalidRestaurantUpdate},
+		{"coordinates", RestaurantPatch{Latitude: ptr(12.9716), Longitude: ptr(77.5946)}, nil},
+		{"latitude alone", RestaurantPatch{Latitude: ptr(12.9716)}, ErrInvalidCoordinates},
+		{"latitude out of range", RestaurantPatch{Latitude: ptr(91.0), Longitude: ptr(77.5946)}, ErrInvalidCoordinates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.patch.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestaurantPatchApplyTo(t *testing.T) {
+	restaurant := &Restaurant{Name: "Old", Locality: "Indiranagar", Pincode: "560038", Latitude: 1, Longitude: 2}
+	patch := RestaurantPatch{Name: ptr(" New "), Pincode: ptr(" 560001 "), Latitude: ptr(12.9716), Longitude: ptr(77.5946)}
+	patch.ApplyTo(restaurant)
+
+	if restaurant.Name != "New" || restaurant.Locality != "Indiranagar" || restaurant.Pincode != "560001" ||
+		restaurant.Latitude != 12.9716 || restaurant.Longitude != 77.5946 {
+		t.Errorf("ApplyTo() = %+v", *restaurant)
+	}
+}
@@ -0,0 +1,140 @@
+package model
+
+import "time"
+
+// Price schedule recurrences.
+const (
+	RecurNone   = ""
+	RecurDaily  = "daily"
+	RecurWeekly = "weekly"
+)
+
+// PriceSchedule changes a product's price for a period. Between StartsAt and the
+// optional EndsAt it either sets Price or, when PercentBasisPoints is set, takes
+// that share off the regular price.
+//
+// A one-off schedule (no recurrence) applies for the whole period and is how
+// owners plan a price change ahead of time. A daily or weekly schedule only
+// applies between StartMinute and EndMinute (minutes since midnight in the
+// restaurant's timezone), on the weekdays in Weekdays for weekly ones: a
+// recurring happy hour. Weekdays is a bit set with bit n standing for
+// time.Weekday(n), so Monday to Friday is 0b0111110.
+type PriceSchedule struct {
+	ID                 uint       `gorm:"column:id;primaryKey" json:"id"`
+	ProductID          string     `gorm:"column:product_id;size:50;index" json:"productId"`
+	Price              Amount     `gorm:"column:price_minor" json:"price"`
+	PercentBasisPoints int        `gorm:"column:percent_bps" json:"percentBasisPoints"`
+	StartsAt           time.Time  `gorm:"column:starts_at" json:"startsAt"`
+	EndsAt             *time.Time `gorm:"column:ends_at;index" json:"endsAt"`
+	Recurrence         string     `gorm:"column:recurrence;size:10" json:"recurrence"`
+	Weekdays           int        `gorm:"column:weekdays" json:"weekdays"`
+	StartMinute        int        `gorm:"column:start_minute" json:"startMinute"`
+	EndMinute          int        `gorm:"column:end_minute" json:"endMinute"`
+	CreatedAt          time.Time  `gorm:"column:created_at" json:"createdAt"`
+}
+
+// Validate checks the period, the price or discount and the recurrence window.
+func (s *PriceSchedule) Validate() error {
+	if s.EndsAt != nil && !s.EndsAt.After(s.StartsAt) {
+		return ErrInvalidPriceSchedule
+	}
+	if s.PercentBasisPoints < 0 || s.PercentBasisPoints > 10000 || s.Price < 0 {
+		return ErrInvalidPriceSchedule
+	}
+	if s.PercentBasisPoints == 0 && s.Price == 0 {
+		// A price of zero is almost certainly a missing field, and giving items
+		// away is done with promotions rather than schedules.
+		return ErrInvalidPriceSchedule
+	}
+
+	switch s.Recurrence {
+	case RecurNone:
+		return nil
+	case RecurDaily, RecurWeekly:
+	default:
+		return ErrInvalidPriceSchedule
+	}
+	if s.StartMinute < 0 || s.StartMinute >= 24*60 || s.EndMinute < 0 || s.EndMinute > 24*60 || s.StartMinute == s.EndMinute {
+		return ErrInvalidPriceSchedule
+	}
+	if s.Recurrence == RecurWeekly {
+		if s.Weekdays <= 0 || s.Weekdays >= 1<<7 {
+			return ErrInvalidPriceSchedule
+		}
+	}
+	return nil
+}
+
+// ActiveAt reports whether the schedule applies at now, with loc being the
+// restaurant's timezone. Like opening hours, a window whose EndMinute is not
+// after StartMinute runs past midnight and belongs to the day it started.
+func (s *PriceSchedule) ActiveAt(now time.Time, loc *time.Location) bool {
+	if now.Before(s.StartsAt) || (s.EndsAt != nil && !now.Before(*s.EndsAt)) {
+		return false
+	}
+	if s.Recurrence == RecurNone {
+		return true
+	}
+
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if s.Recurrence == RecurWeekly && s.Weekdays&(1<<day.Weekday()) == 0 {
+			continue
+		}
+		start := atMinute(day, 0, s.StartMinute)
+		end := atMinute(day, 0, s.EndMinute)
+		if s.EndMinute <= s.StartMinute {
+			end = atMinute(day, 1, s.EndMinute)
+		}
+		if !now.Before(start) && now.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// priceFrom is the price the schedule gives for a product whose price is base.
+func (s *PriceSchedule) priceFrom(base Amount) Amount {
+	if s.PercentBasisPoints > 0 {
+		return base - roundDiv(base*Amount(s.PercentBasisPoints), 10000)
+	}
+	return s.Price
+}
+
+// EffectivePrice works out what a product costs at now. The most recently started
+// one-off price change replaces the listed price; recurring schedules are then
+// applied to that, and the lowest price any of them gives wins, so overlapping
+// happy hours never make an item dearer.
+func EffectivePrice(p *Product, schedules []*PriceSchedule, loc *time.Location, now time.Time) Amount {
+	base := p.Price
+	var baseSince time.Time
+	for _, s := range schedules {
+		if s.Recurrence != RecurNone || s.PercentBasisPoints > 0 || !s.ActiveAt(now, loc) {
+			continue
+		}
+		if baseSince.IsZero() || s.StartsAt.After(baseSince) {
+			base, baseSince = s.Price, s.StartsAt
+		}
+	}
+
+	price := base
+	for _, s := range schedules {
+		if (s.Recurrence == RecurNone && s.PercentBasisPoints == 0) || !s.ActiveAt(now, loc) {
+			continue
+		}
+		if candidate := s.priceFrom(base); candidate < price {
+			price = candidate
+		}
+	}
+	return price
+}
+
+// SellingPrice is the price to show and charge: the effective price once price
+// schedules have been applied, or the listed price otherwise.
+func (p *Product) SellingPrice() Amount {
+	if p.EffectivePrice != nil {
+		return *p.EffectivePrice
+	}
+	return p.Price
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriceScheduleActiveAt(t *testing.T) {
+	kolkata := mustLoad(t, DefaultTimezone)
+	london := mustLoad(t, "Europe/London")
+	at := func(day, h, m int) time.Time { return time.Date(2026, 10, day, h, m, 0, 0, kolkata) }
+	weekdays := 0b0111110
+	ends := at(20, 0, 0)
+
+	tests := []struct {
+		name     string
+		schedule PriceSchedule
+		loc      *time.Location
+		now      time.Time
+		want     bool
+	}{
+		{
+			name:     "one-off before start",
+			schedule: PriceSchedule{StartsAt: at(16, 12, 0)},
+			now:      at(16, 11, 59),
+			want:     false,
+		},
+		{
+			name:     "one-off running",
+			schedule: PriceSchedule{StartsAt: at(16, 12, 0), EndsAt: &ends},
+			now:      at(18, 3, 0),
+			want:     true,
+		},
+		{
+			name:     "one-off end is exclusive",
+			schedule: PriceSchedule{StartsAt: at(16, 12, 0), EndsAt: &ends},
+			now:      ends,
+			want:     false,
+		},
+		{
+			name:     "daily inside window",
+			schedule: PriceSchedule{Recurrence: RecurDaily, StartMinute: 15 * 60, EndMinute: 18 * 60},
+			now:      at(16, 16, 0),
+			want:     true,
+		},
+		{
+			name:     "daily outside window",
+			schedule: PriceSchedule{Recurrence: RecurDaily, StartMinute: 15 * 60, EndMinute: 18 * 60},
+			now:      at(16, 18, 0),
+			want:     false,
+		},
+		{
+			name:     "daily window past midnight",
+			schedule: PriceSchedule{Recurrence: RecurDaily, StartMinute: 22 * 60, EndMinute: 60},
+			now:      at(17, 0, 30),
+			want:     true,
+		},
+		{
+			name:     "weekly on a listed day",
+			schedule: PriceSchedule{Recurrence: RecurWeekly, Weekdays: weekdays, StartMinute: 15 * 60, EndMinute: 18 * 60},
+			now:      at(16, 16, 0), // Friday
+			want:     true,
+		},
+		{
+			name:     "weekly on another day",
+			schedule: PriceSchedule{Recurrence: RecurWeekly, Weekdays: weekdays, StartMinute: 15 * 60, EndMinute: 18 * 60},
+			now:      at(17, 16, 0), // Saturday
+			want:     false,
+		},
+		{
+			name:     "weekly overnight belongs to the day it started",
+			schedule: PriceSchedule{Recurrence: RecurWeekly, Weekdays: weekdays, StartMinute: 22 * 60, EndMinute: 2 * 60},
+			now:      at(17, 1, 0), // Saturday, in Friday's window
+			want:     true,
+		},
+		{
+			name:     "read in the restaurant timezone",
+			schedule: PriceSchedule{Recurrence: RecurDaily, StartMinute: 15 * 60, EndMinute: 18 * 60},
+			loc:      london,
+			now:      time.Date(2026, 10, 16, 16, 0, 0, 0, london),
+			want:     true,
+		},
+		{
+			// Clocks went back at 02:00 on 2026-10-25; midnight plus nine hours
+			// would be 08:00 local
+			name:     "window on a DST change day",
+			schedule: PriceSchedule{Recurrence: RecurDaily, StartMinute: 9 * 60, EndMinute: 10 * 60},
+			loc:      london,
+			now:      time.Date(2026, 10, 25, 8, 30, 0, 0, london),
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := tt.loc
+			if loc == nil {
+				loc = kolkata
+			}
+			if got := tt.schedule.ActiveAt(tt.now, loc); got != tt.want {
+				t.Errorf("ActiveAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectivePrice(t *testing.T) {
+	kolkata := mustLoad(t, DefaultTimezone)
+	now := time.Date(2026, 10, 16, 16, 0, 0, 0, kolkata)
+	earlier, later := now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+	future := now.Add(24 * time.Hour)
+	happyHour := func(bps int, price Amount) *PriceSchedule {
+		return &PriceSchedule{Recurrence: RecurDaily, StartMinute: 15 * 60, EndMinute: 18 * 60, PercentBasisPoints: bps, Price: price}
+	}
+
+	tests := []struct {
+		name      string
+		schedules []*PriceSchedule
+		want      Amount
+	}{
+		{"no schedules", nil, 20000},
+		{"one-off price change", []*PriceSchedule{{StartsAt: earlier, Price: 18000}}, 18000},
+		{"future price change", []*PriceSchedule{{StartsAt: future, Price: 18000}}, 20000},
+		{
+			name:      "latest one-off wins",
+			schedules: []*PriceSchedule{{StartsAt: later, Price: 17000}, {StartsAt: earlier, Price: 18000}},
+			want:      17000,
+		},
+		{"happy hour percent", []*PriceSchedule{happyHour(2500, 0)}, 15000},
+		{"happy hour fixed price", []*PriceSchedule{happyHour(0, 16000)}, 16000},
+		{
+			name:      "happy hour applies to the changed price",
+			schedules: []*PriceSchedule{{StartsAt: earlier, Price: 18000}, happyHour(1000, 0)},
+			want:      16200,
+		},
+		{
+			name:      "overlapping happy hours take the lowest",
+			schedules: []*PriceSchedule{happyHour(1000, 0), happyHour(0, 15000), happyHour(2000, 0)},
+			want:      15000,
+		},
+		{"happy hour never raises the price", []*PriceSchedule{happyHour(0, 25000)}, 20000},
+		{
+			name: "happy hour outside its window",
+			schedules: []*PriceSchedule{{Recurrence: RecurDaily, StartMinute: 9 * 60, EndMinute: 10 * 60,
+				PercentBasisPoints: 5000}},
+			want: 20000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := &Product{Price: 20000}
+			if got := EffectivePrice(product, tt.schedules, kolkata, now); got != tt.want {
+				t.Errorf("EffectivePrice() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceScheduleValidate(t *testing.T) {
+	start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		schedule PriceSchedule
+		wantErr  bool
+	}{
+		{"one-off price", PriceSchedule{StartsAt: start, Price: 100}, false},
+		{"one-off without price or percent", PriceSchedule{StartsAt: start}, true},
+		{"daily without price or percent", PriceSchedule{Recurrence: RecurDaily, StartMinute: 60, EndMinute: 120}, true},
+		{"weekly without price or percent", PriceSchedule{Recurrence: RecurWeekly, Weekdays: 1, StartMinute: 60, EndMinute: 120}, true},
+		{"end before start", PriceSchedule{StartsAt: start, EndsAt: &before, Price: 100}, true},
+		{"percent over 100", PriceSchedule{StartsAt: start, PercentBasisPoints: 10001}, true},
+		{"empty window", PriceSchedule{Recurrence: RecurDaily, StartMinute: 60, EndMinute: 60, PercentBasisPoints: 500}, true},
+		{"weekly without days", PriceSchedule{Recurrence: RecurWeekly, StartMinute: 60, EndMinute: 120, PercentBasisPoints: 500}, true},
+		{"unknown recurrence", PriceSchedule{Recurrence: "monthly", StartMinute: 60, EndMinute: 120, PercentBasisPoints: 500}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.schedule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
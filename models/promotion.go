@@ -0,0 +1,274 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Promotion rule types.
+const (
+	PromoPercentCategory = "percent_category"
+	PromoFlatThreshold   = "flat_threshold"
+	PromoBuyXGetY        = "buy_x_get_y"
+)
+
+// Promotion is an automatic offer on a restaurant's catalog.
+//
+//   - percent_category: PercentBasisPoints off every line in Category.
+//   - flat_threshold: FlatAmount off once the cart reaches MinSubtotal.
+//   - buy_x_get_y: for every BuyQuantity of BuyProductID, GetQuantity of
+//     GetProductID are free.
+//
+// Non-stackable promotions are never combined with others; see ApplyPromotions.
+type Promotion struct {
+	ID                 string     `gorm:"column:id;size:100" json:"id"`
+	RestaurantID       string     `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	Name               string     `gorm:"column:name" json:"name"`
+	RuleType           string     `gorm:"column:rule_type;size:30" json:"ruleType"`
+	Category           string     `gorm:"column:category" json:"category"`
+	PercentBasisPoints int        `gorm:"column:percent_bps" json:"percentBasisPoints"`
+	FlatAmount         Amount     `gorm:"column:flat_amount_minor" json:"flatAmount"`
+	MinSubtotal        Amount     `gorm:"column:min_subtotal_minor" json:"minSubtotal"`
+	BuyProductID       string     `gorm:"column:buy_product_id;size:100" json:"buyProductId"`
+	BuyQuantity        int32      `gorm:"column:buy_quantity" json:"buyQuantity"`
+	GetProductID       string     `gorm:"column:get_product_id;size:100" json:"getProductId"`
+	GetQuantity        int32      `gorm:"column:get_quantity" json:"getQuantity"`
+	StartsAt           time.Time  `gorm:"column:starts_at" json:"startsAt"`
+	EndsAt             *time.Time `gorm:"column:ends_at" json:"endsAt"`
+	PerCustomerLimit   int        `gorm:"column:per_customer_limit" json:"perCustomerLimit"`
+	Stackable          bool       `gorm:"column:stackable" json:"stackable"`
+	Active             bool       `gorm:"column:active;index" json:"active"`
+}
+
+// PromotionUsage records that a customer's order used a promotion.
+type PromotionUsage struct {
+	ID          uint      `gorm:"column:id;primaryKey" json:"id"`
+	PromotionID string    `gorm:"column:promotion_id;size:100;index:idx_promo_usage_customer;uniqueIndex:idx_promo_usage_order" json:"promotionId"`
+	CustomerID  string    `gorm:"column:customer_id;size:100;index:idx_promo_usage_customer" json:"customerId"`
+	OrderID     string    `gorm:"column:order_id;size:100;index;uniqueIndex:idx_promo_usage_order" json:"orderId"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"createdAt"`
+}
+
+// AppliedPromotion explains a discount that made it into the quote.
+type AppliedPromotion struct {
+	PromotionID string `json:"promotionId"`
+	Name        string `json:"name"`
+	Discount    Amount `json:"discount"`
+	Explanation string `json:"explanation"`
+}
+
+// SkippedPromotion explains why an active promotion did not apply.
+type SkippedPromotion struct {
+	PromotionID string `json:"promotionId"`
+	Name        string `json:"name"`
+	Reason      string `json:"reason"`
+}
+
+// Validate checks that the fields needed by the rule type are set.
+func (p *Promotion) Validate() error {
+	if p.EndsAt != nil && !p.EndsAt.After(p.StartsAt) {
+		return ErrInvalidPromotion
+	}
+	if p.PerCustomerLimit < 0 {
+		return ErrInvalidPromotion
+	}
+	switch p.RuleType {
+	case PromoPercentCategory:
+		if p.Category == "" || p.PercentBasisPoints <= 0 || p.PercentBasisPoints > 10000 {
+			return ErrInvalidPromotion
+		}
+	case PromoFlatThreshold:
+		if p.FlatAmount <= 0 || p.MinSubtotal < 0 {
+			return ErrInvalidPromotion
+		}
+	case PromoBuyXGetY:
+		if p.BuyProductID == "" || p.GetProductID == "" || p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return ErrInvalidPromotion
+		}
+	default:
+		return ErrInvalidPromotion
+	}
+	return nil
+}
+
+// IsLive reports whether the promotion is switched on and inside its validity window.
+func (p *Promotion) IsLive(now time.Time) bool {
+	if !p.Active || now.Before(p.StartsAt) {
+		return false
+	}
+	return p.EndsAt == nil || now.Before(*p.EndsAt)
+}
+
+// evaluate works out the discount per quote line. It returns a reason instead
+// when the cart does not qualify.
+func (p *Promotion) evaluate(lines []*QuoteLine, products map[string]*Product, currency string) ([]Amount, string, string) {
+	discounts := make([]Amount, len(lines))
+
+	switch p.RuleType {
+	case PromoPercentCategory:
+		matched := false
+		for i, l := range lines {
+			if products[l.ProductID].Category != p.Category {
+				continue
+			}
+			matched = true
+			discounts[i] = roundDiv(l.LineTotal*Amount(p.PercentBasisPoints), 10000)
+		}
+		if !matched {
+			return nil, "", fmt.Sprintf("no items in category %q", p.Category)
+		}
+		return discounts, fmt.Sprintf("%s%% off %s items", formatBasisPoints(p.PercentBasisPoints), p.Category), ""
+
+	case PromoFlatThreshold:
+		var subtotal Amount
+		for _, l := range lines {
+			subtotal += l.LineTotal
+		}
+		if subtotal < p.MinSubtotal || subtotal == 0 {
+			return nil, "", fmt.Sprintf("cart total %s is below the minimum of %s",
+				formatAmount(subtotal, currency), formatAmount(p.MinSubtotal, currency))
+		}
+		flat := p.FlatAmount
+		if flat > subtotal {
+			flat = subtotal
+		}
+		// Spread the flat discount over the lines in proportion to their totals
+		// so the tax on each rate is reduced fairly; the remainder goes to the last line.
+		var allocated Amount
+		for i, l := range lines {
+			discounts[i] = flat * l.LineTotal / subtotal
+			allocated += discounts[i]
+		}
+		discounts[len(lines)-1] += flat - allocated
+		return discounts, fmt.Sprintf("flat %s off orders of %s or more",
+			formatAmount(p.FlatAmount, currency), formatAmount(p.MinSubtotal, currency)), ""
+
+	case PromoBuyXGetY:
+		var bought, eligible int32
+		getIndex := -1
+		for i, l := range lines {
+			if l.ProductID == p.BuyProductID {
+				bought += l.Quantity
+			}
+			if l.ProductID == p.GetProductID {
+				eligible += l.Quantity
+				getIndex = i
+			}
+		}
+		sets := bought / p.BuyQuantity
+		if p.BuyProductID == p.GetProductID {
+			// The free units come out of the same line, so each set needs buy+get units.
+			sets = bought / (p.BuyQuantity + p.GetQuantity)
+		}
+		free := sets * p.GetQuantity
+		if free > eligible {
+			free = eligible
+		}
+		if free == 0 || getIndex < 0 {
+			return nil, "", fmt.Sprintf("buy %d to get %d free", p.BuyQuantity, p.GetQuantity)
+		}
+		discounts[getIndex] = lines[getIndex].UnitPrice * Amount(free)
+		return discounts, fmt.Sprintf("buy %d get %d free: %d free", p.BuyQuantity, p.GetQuantity, free), ""
+	}
+
+	return nil, "", "unsupported rule"
+}
+
+// ApplyPromotions evaluates every promotion against the quote, picks the best
+// combination and records the per-line discounts. Stackable promotions combine;
+// a non-stackable one applies alone, and only when it beats all stackable ones
+// together. Taxes must be recomputed afterwards.
+func ApplyPromotions(quote *PriceQuote, products map[string]*Product, promotions []*Promotion) ([]*AppliedPromotion, []*SkippedPromotion) {
+	type candidate struct {
+		promo     *Promotion
+		discounts []Amount
+		total     Amount
+		explain   string
+	}
+
+	var stackable, exclusive []candidate
+	var skipped []*SkippedPromotion
+	for _, p := range promotions {
+		discounts, explain, reason := p.evaluate(quote.Lines, products, quote.Currency)
+		if reason != "" {
+			skipped = append(skipped, &SkippedPromotion{PromotionID: p.ID, Name: p.Name, Reason: reason})
+			continue
+		}
+		c := candidate{promo: p, discounts: discounts, explain: explain}
+		for _, d := range discounts {
+			c.total += d
+		}
+		if p.Stackable {
+			stackable = append(stackable, c)
+		} else {
+			exclusive = append(exclusive, c)
+		}
+	}
+
+	sort.SliceStable(exclusive, func(i, j int) bool { return exclusive[i].total > exclusive[j].total })
+	var stackTotal Amount
+	for _, c := range stackable {
+		stackTotal += c.total
+	}
+
+	chosen := stackable
+	var loser string
+	if len(exclusive) > 0 && exclusive[0].total > stackTotal {
+		chosen = []candidate{exclusive[0]}
+		loser = fmt.Sprintf("%s gives a bigger discount and cannot be combined", exclusive[0].promo.Name)
+		for _, c := range stackable {
+			skipped = append(skipped, &SkippedPromotion{PromotionID: c.promo.ID, Name: c.promo.Name, Reason: loser})
+		}
+		exclusive = exclusive[1:]
+	}
+	for _, c := range exclusive {
+		skipped = append(skipped, &SkippedPromotion{
+			PromotionID: c.promo.ID,
+			Name:        c.promo.Name,
+			Reason:      "cannot be combined with other offers and a better offer applies",
+		})
+	}
+
+	var applied []*AppliedPromotion
+	for _, c := range chosen {
+		var total Amount
+		for i, d := range c.discounts {
+			// Never discount a line below zero when several offers stack on it.
+			room := quote.Lines[i].LineTotal - quote.Lines[i].Discount
+			if d > room {
+				d = room
+			}
+			quote.Lines[i].Discount += d
+			total += d
+		}
+		if total == 0 {
+			continue
+		}
+		applied = append(applied, &AppliedPromotion{
+			PromotionID: c.promo.ID,
+			Name:        c.promo.Name,
+			Discount:    total,
+			Explanation: c.explain,
+		})
+	}
+	return applied, skipped
+}
+
+func formatBasisPoints(bps int) string {
+	if bps%100 == 0 {
+		return fmt.Sprintf("%d", bps/100)
+	}
+	return fmt.Sprintf("%d.%02d", bps/100, bps%100)
+}
+
+// formatAmount writes an amount in major units with the currency's precision,
+// the way customers see prices.
+func formatAmount(a Amount, currency string) string {
+	exp, err := CurrencyExponent(currency)
+	if err != nil {
+		exp = currencyExponents[DefaultCurrency]
+	}
+	return strconv.FormatFloat(a.Major(currency), 'f', exp, 64)
+}
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyPromotions(t *testing.T) {
+	products := map[string]*Product{
+		"burger": {ID: "burger", Category: "mains", Price: 20000},
+		"coke":   {ID: "coke", Category: "drinks", Price: 5000},
+	}
+	// Two burgers and three cokes: 550.00 before discounts.
+	cart := func() *PriceQuote {
+		return &PriceQuote{Lines: []*QuoteLine{
+			{ProductID: "burger", Quantity: 2, UnitPrice: 20000, LineTotal: 40000},
+			{ProductID: "coke", Quantity: 3, UnitPrice: 5000, LineTotal: 15000},
+		}}
+	}
+	mainsOff := func(id string, bps int, stackable bool) *Promotion {
+		return &Promotion{ID: id, Name: id, RuleType: PromoPercentCategory, Category: "mains", PercentBasisPoints: bps, Stackable: stackable}
+	}
+	drinksOff := func(id string, bps int) *Promotion {
+		return &Promotion{ID: id, Name: id, RuleType: PromoPercentCategory, Category: "drinks", PercentBasisPoints: bps, Stackable: true}
+	}
+	flat := func(id string, amount, min Amount, stackable bool) *Promotion {
+		return &Promotion{ID: id, Name: id, RuleType: PromoFlatThreshold, FlatAmount: amount, MinSubtotal: min, Stackable: stackable}
+	}
+	buyGet := func(id, buy string, buyQty int32, get string, getQty int32) *Promotion {
+		return &Promotion{ID: id, Name: id, RuleType: PromoBuyXGetY,
+			BuyProductID: buy, BuyQuantity: buyQty, GetProductID: get, GetQuantity: getQty, Stackable: true}
+	}
+
+	tests := []struct {
+		name       string
+		promotions []*Promotion
+		discounts  []Amount          // per cart line
+		applied    map[string]Amount // promotion ID to discount
+		skipped    []string
+	}{
+		{
+			name:      "no promotions",
+			discounts: []Amount{0, 0},
+			applied:   map[string]Amount{},
+		},
+		{
+			name:       "percent off a category",
+			promotions: []*Promotion{mainsOff("mains10", 1000, true)},
+			discounts:  []Amount{4000, 0},
+			applied:    map[string]Amount{"mains10": 4000},
+		},
+		{
+			name:       "category not in the cart",
+			promotions: []*Promotion{{ID: "desserts", RuleType: PromoPercentCategory, Category: "desserts", PercentBasisPoints: 1000, Stackable: true}},
+			discounts:  []Amount{0, 0},
+			applied:    map[string]Amount{},
+			skipped:    []string{"desserts"},
+		},
+		{
+			// 50.00 split 400:150 over the lines, the remainder going to the last.
+			name:       "flat threshold spread over lines",
+			promotions: []*Promotion{flat("flat50", 5000, 50000, true)},
+			discounts:  []Amount{3636, 1364},
+			applied:    map[string]Amount{"flat50": 5000},
+		},
+		{
+			name:       "flat threshold not reached",
+			promotions: []*Promotion{flat("flat50", 5000, 60000, true)},
+			discounts:  []Amount{0, 0},
+			applied:    map[string]Amount{},
+			skipped:    []string{"flat50"},
+		},
+		{
+			name:       "stackable promotions combine",
+			promotions: []*Promotion{mainsOff("mains10", 1000, true), flat("flat50", 5000, 50000, true)},
+			discounts:  []Amount{7636, 1364},
+			applied:    map[string]Amount{"mains10": 4000, "flat50": 5000},
+		},
+		{
+			name:       "exclusive beats the stack",
+			promotions: []*Promotion{mainsOff("mains10", 1000, true), flat("flat100", 10000, 0, false)},
+			discounts:  []Amount{7272, 2728},
+			applied:    map[string]Amount{"flat100": 10000},
+			skipped:    []string{"mains10"},
+		},
+		{
+			name:       "stack beats the exclusive",
+			promotions: []*Promotion{mainsOff("mains10", 1000, true), flat("flat10", 1000, 0, false)},
+			discounts:  []Amount{4000, 0},
+			applied:    map[string]Amount{"mains10": 4000},
+			skipped:    []string{"flat10"},
+		},
+		{
+			name:       "only the best exclusive applies",
+			promotions: []*Promotion{mainsOff("mains10", 1000, false), mainsOff("mains20", 2000, false)},
+			discounts:  []Amount{8000, 0},
+			applied:    map[string]Amount{"mains20": 8000},
+			skipped:    []string{"mains10"},
+		},
+		{
+			name:       "buy a burger get a coke",
+			promotions: []*Promotion{buyGet("burgerCoke", "burger", 1, "coke", 1)},
+			discounts:  []Amount{0, 10000},
+			applied:    map[string]Amount{"burgerCoke": 10000},
+		},
+		{
+			// Three cokes make one set of two bought and one free.
+			name:       "buy two get one of the same product",
+			promotions: []*Promotion{buyGet("coke3for2", "coke", 2, "coke", 1)},
+			discounts:  []Amount{0, 5000},
+			applied:    map[string]Amount{"coke3for2": 5000},
+		},
+		{
+			name:       "buy-x-get-y not reached",
+			promotions: []*Promotion{buyGet("burger3", "burger", 3, "coke", 1)},
+			discounts:  []Amount{0, 0},
+			applied:    map[string]Amount{},
+			skipped:    []string{"burger3"},
+		},
+		{
+			name:       "stacked discounts never exceed the line",
+			promotions: []*Promotion{drinksOff("drinks80", 8000), drinksOff("drinks50", 5000)},
+			discounts:  []Amount{0, 15000},
+			applied:    map[string]Amount{"drinks80": 12000, "drinks50": 3000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := cart()
+			applied, skipped := ApplyPromotions(quote, products, tt.promotions)
+
+			for i, l := range quote.Lines {
+				if l.Discount != tt.discounts[i] {
+					t.Errorf("line %s discount = %d, want %d", l.ProductID, l.Discount, tt.discounts[i])
+				}
+			}
+			gotApplied := map[string]Amount{}
+			for _, a := range applied {
+				gotApplied[a.PromotionID] = a.Discount
+			}
+			if !reflect.DeepEqual(gotApplied, tt.applied) {
+				t.Errorf("applied = %v, want %v", gotApplied, tt.applied)
+			}
+			var gotSkipped []string
+			for _, s := range skipped {
+				if s.Reason == "" {
+					t.Errorf("skipped %s without a reason", s.PromotionID)
+				}
+				gotSkipped = append(gotSkipped, s.PromotionID)
+			}
+			if !reflect.DeepEqual(gotSkipped, tt.skipped) {
+				t.Errorf("skipped = %v, want %v", gotSkipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestPromotionReasonsUseMajorUnits(t *testing.T) {
+	products := map[string]*Product{"ramen": {ID: "ramen", Price: 850}}
+	tests := []struct {
+		currency string
+		want     string
+	}{
+		{"INR", "cart total 8.50 is below the minimum of 10.00"},
+		{"JPY", "cart total 850 is below the minimum of 1000"},
+		{"KWD", "cart total 0.850 is below the minimum of 1.000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			quote := &PriceQuote{Currency: tt.currency, Lines: []*QuoteLine{
+				{ProductID: "ramen", Quantity: 1, UnitPrice: 850, LineTotal: 850},
+			}}
+			promotions := []*Promotion{{ID: "flat", RuleType: PromoFlatThreshold, FlatAmount: 100, MinSubtotal: 1000}}
+			_, skipped := ApplyPromotions(quote, products, promotions)
+			if len(skipped) != 1 || skipped[0].Reason != tt.want {
+				t.Fatalf("skipped = %+v, want reason %q", skipped, tt.want)
+			}
+		})
+	}
+}
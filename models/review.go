@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Review visibility states.
+const (
+	ReviewVisible = "visible"
+	ReviewHidden  = "hidden"
+)
+
+// Review is a customer's rating of a restaurant, or of one of its products when
+// ProductID is set. A customer can review each order (and each product in it) once.
+type Review struct {
+	ID             string     `gorm:"column:id;size:100" json:"id"`
+	RestaurantID   string     `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	ProductID      string     `gorm:"column:product_id;size:100;index;uniqueIndex:idx_review_order_product" json:"productId"`
+	CustomerID     string     `gorm:"column:customer_id;size:100;index" json:"customerId"`
+	OrderID        string     `gorm:"column:order_id;size:100;uniqueIndex:idx_review_order_product" json:"orderId"`
+	Rating         int        `gorm:"column:rating" json:"rating"`
+	Text           string     `gorm:"column:text;type:text" json:"text"`
+	OwnerReply     string     `gorm:"column:owner_reply;type:text" json:"ownerReply"`
+	OwnerRepliedAt *time.Time `gorm:"column:owner_replied_at" json:"ownerRepliedAt"`
+	Status         string     `gorm:"column:status;size:20;index" json:"status"`
+	ModeratedBy    string     `gorm:"column:moderated_by;size:100" json:"moderatedBy"`
+	ModerationNote string     `gorm:"column:moderation_note;type:text" json:"moderationNote"`
+	CreatedAt      time.Time  `gorm:"column:created_at" json:"createdAt"`
+}
+
+// RatingSummary is the aggregate rating of a restaurant or product.
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int64   `json:"count"`
+}
+
+// NewRatingSummary derives the average from the stored total and count.
+func NewRatingSummary(total, count int64) RatingSummary {
+	if count == 0 {
+		return RatingSummary{}
+	}
+	return RatingSummary{Average: float64(total) / float64(count), Count: count}
+}
+
+// Rating returns the restaurant's aggregate rating.
+func (r *Restaurant) Rating() RatingSummary {
+	return NewRatingSummary(r.RatingTotal, r.RatingCount)
+}
+
+// Rating returns the product's aggregate rating.
+func (p *Product) Rating() RatingSummary {
+	return NewRatingSummary(p.RatingTotal, p.RatingCount)
+}
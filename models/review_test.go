@@ -0,0 +1,23 @@
+package model
+
+import "testing"
+
+func TestNewRatingSummary(t *testing.T) {
+	tests := []struct {
+		name         string
+		total, count int64
+		want         RatingSummary
+	}{
+		{"no reviews", 0, 0, RatingSummary{}},
+		{"one review", 4, 1, RatingSummary{Average: 4, Count: 1}},
+		{"fractional average", 14, 3, RatingSummary{Average: 14.0 / 3, Count: 3}},
+		{"large counts", 4_500_000, 1_000_000, RatingSummary{Average: 4.5, Count: 1_000_000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRatingSummary(tt.total, tt.count); got != tt.want {
+				t.Errorf("NewRatingSummary(%d, %d) = %+v, want %+v", tt.total, tt.count, got, tt.want)
+			}
+		})
+	}
+}
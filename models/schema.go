@@ -1,25 +1,60 @@
 package model
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Restaurant struct {
-	ID           string `gorm:"column:id;size:100" json:"id"`
-	OwnerEmail   string `gorm:"column:owner_email" json:"ownerEmail"`
-	PasswordHash string `gorm:"column:password_hash" json:"passwordHash"`
-	Name         string `gorm:"column:name" json:"name"`
-	PhoneNumber  uint64 `gorm:"column:phone_number" json:"phoneNumber"`
-	IsBanned     bool   `gorm:"column:is_banned" json:"isBanned"`
-	BanReason    string `gorm:"column:ban_reason" json:"banReason"`
-	StreetName   string `gorm:"column:street_name" json:"streetName"`
-	Locality     string `gorm:"column:locality" json:"locality"`
-	State        string `gorm:"column:state" json:"state"`
-	Pincode      string `gorm:"column:pincode" json:"pincode"`
+	ID           string    `gorm:"column:id;size:100" json:"id"`
+	OwnerEmail   *string   `gorm:"column:owner_email;size:191;uniqueIndex" json:"ownerEmail"`
+	PasswordHash string    `gorm:"column:password_hash" json:"passwordHash"`
+	Name         string    `gorm:"column:name" json:"name"`
+	PhoneNumber  uint64    `gorm:"column:phone_number" json:"phoneNumber"`
+	IsBanned     bool      `gorm:"column:is_banned" json:"isBanned"`
+	BanReason    string    `gorm:"column:ban_reason" json:"banReason"`
+	StreetName   string    `gorm:"column:street_name" json:"streetName"`
+	Locality     string    `gorm:"column:locality" json:"locality"`
+	State        string    `gorm:"column:state" json:"state"`
+	Pincode      string    `gorm:"column:pincode" json:"pincode"`
+	Latitude     float64   `gorm:"column:latitude;index:idx_restaurant_lat_lng" json:"latitude"`
+	Longitude    float64   `gorm:"column:longitude;index:idx_restaurant_lat_lng" json:"longitude"`
+	Timezone     string    `gorm:"column:timezone;size:64" json:"timezone"`
+	IsPaused     bool      `gorm:"column:is_paused" json:"isPaused"`
+	BrandID      string    `gorm:"column:brand_id;size:100;index" json:"brandId"`
+	Status       string    `gorm:"column:status;size:20;default:approved;index" json:"status"`
+	RatingTotal  int64     `gorm:"column:rating_total" json:"ratingTotal"`
+	RatingCount  int64     `gorm:"column:rating_count" json:"ratingCount"`
+	Currency     string    `gorm:"column:currency;size:3;default:INR" json:"currency"`
+	CreatedAt    time.Time `gorm:"column:created_at;index" json:"createdAt"`
+	UpdatedAt    time.Time `gorm:"column:updated_at" json:"updatedAt"`
+	CreatedBy    string    `gorm:"column:created_by;size:100" json:"createdBy"`
+	UpdatedBy    string    `gorm:"column:updated_by;size:100" json:"updatedBy"`
+	Version      int64     `gorm:"column:version;not null;default:1" json:"version"`
+
+	Products []*Product `gorm:"foreignKey:RestaurantID;references:ID" json:"products,omitempty"`
 }
 
 type Product struct {
-	ID           string  `gorm:"column:id;size:50" json:"id"`
-	RestaurantID string  `gorm:"column:restaurant_id;size:50" json:"restaurantId"`
-	Name         string  `gorm:"column:name" json:"name"`
-	Description  string  `gorm:"column:description" json:"description"`
-	Price        float64 `gorm:"column:price" json:"price"`
-	Stock        int32   `gorm:"column:stock" json:"stock"`
-	Category     string  `gorm:"column:category" json:"category"`
+	ID              string         `gorm:"column:id;size:50" json:"id"`
+	RestaurantID    string         `gorm:"column:restaurant_id;size:50;uniqueIndex:idx_product_restaurant_sku" json:"restaurantId"`
+	Name            string         `gorm:"column:name" json:"name"`
+	Description     string         `gorm:"column:description" json:"description"`
+	Price           Amount         `gorm:"column:price_minor" json:"price"`
+	Stock           int32          `gorm:"column:stock" json:"stock"`
+	Category        string         `gorm:"column:category" json:"category"`
+	SKU             *string        `gorm:"column:sku;size:100;uniqueIndex:idx_product_restaurant_sku" json:"sku"`
+	BrandMenuItemID string         `gorm:"column:brand_menu_item_id;size:100;index" json:"brandMenuItemId"`
+	RatingTotal     int64          `gorm:"column:rating_total" json:"ratingTotal"`
+	RatingCount     int64          `gorm:"column:rating_count" json:"ratingCount"`
+	TaxClassID      string         `gorm:"column:tax_class_id;size:100;index" json:"taxClassId"`
+	TaxInclusive    bool           `gorm:"column:tax_inclusive" json:"taxInclusive"`
+	CreatedAt       time.Time      `gorm:"column:created_at;index" json:"createdAt"`
+	UpdatedAt       time.Time      `gorm:"column:updated_at" json:"updatedAt"`
+	CreatedBy       string         `gorm:"column:created_by;size:100" json:"createdBy"`
+	UpdatedBy       string         `gorm:"column:updated_by;size:100" json:"updatedBy"`
+	Version         int64          `gorm:"column:version;not null;default:1" json:"version"`
+	DeletedAt       gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deletedAt"`
+	EffectivePrice  *Amount        `gorm:"-" json:"effectivePrice,omitempty"`
 }
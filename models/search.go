@@ -0,0 +1,88 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Search result kinds.
+const (
+	SearchHitRestaurant = "restaurant"
+	SearchHitProduct    = "product"
+)
+
+// SearchQuery is a free-text catalog search. Pincode optionally narrows results
+// to restaurants in one area.
+type SearchQuery struct {
+	Text    string
+	Pincode string
+	Limit   int
+}
+
+// SearchHit is one ranked result. Product is nil for restaurant hits.
+type SearchHit struct {
+	Kind       string      `json:"kind"`
+	Restaurant *Restaurant `json:"restaurant"`
+	Product    *Product    `json:"product"`
+	Score      float64     `json:"score"`
+}
+
+// SearchTerms splits free text into lower-case word tokens, dropping punctuation
+// and full-text operators so user input cannot change the query's meaning.
+func SearchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// BooleanModeTerms turns each term into its own MySQL boolean-mode expression,
+// the last one treated as a prefix for typeahead.
+func BooleanModeTerms(terms []string) []string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = t
+		if i == len(terms)-1 {
+			parts[i] += "*"
+		}
+	}
+	return parts
+}
+
+// BooleanModeQuery is a boolean-mode expression requiring every term. It only
+// works within a single MATCH; terms spread over several need BooleanModeTerms.
+func BooleanModeQuery(terms []string) string {
+	parts := BooleanModeTerms(terms)
+	for i := range parts {
+		parts[i] = "+" + parts[i]
+	}
+	return strings.Join(parts, " ")
+}
+
+// BooleanModeAny is a boolean-mode expression matching any term, for ranking
+// rows by how well they match.
+func BooleanModeAny(terms []string) string {
+	return strings.Join(BooleanModeTerms(terms), " ")
+}
+
+// TextScore is the relevance used when full-text indexes are unavailable. Each
+// term found in a field scores 1, or 2 when a word in the field starts with it.
+func TextScore(terms []string, fields ...string) float64 {
+	var score float64
+	for _, field := range fields {
+		lower := strings.ToLower(field)
+		words := SearchTerms(lower)
+		for _, t := range terms {
+			if !strings.Contains(lower, t) {
+				continue
+			}
+			score++
+			for _, w := range words {
+				if strings.HasPrefix(w, t) {
+					score++
+					break
+				}
+			}
+		}
+	}
+	return score
+}
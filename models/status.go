@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Restaurant onboarding states.
+const (
+	StatusPendingReview = "pending_review"
+	StatusApproved      = "approved"
+	StatusRejected      = "rejected"
+	StatusNeedsChanges  = "needs_changes"
+	StatusSuspended     = "suspended"
+)
+
+// statusTransitions lists the states each state may move to.
+var statusTransitions = map[string][]string{
+	StatusPendingReview: {StatusApproved, StatusRejected, StatusNeedsChanges},
+	StatusNeedsChanges:  {StatusPendingReview, StatusRejected},
+	StatusApproved:      {StatusSuspended},
+	StatusSuspended:     {StatusApproved},
+}
+
+// CanTransition reports whether a restaurant may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// RestaurantStatusTransition records one move through the onboarding state machine.
+type RestaurantStatusTransition struct {
+	ID           uint      `gorm:"column:id;primaryKey" json:"id"`
+	RestaurantID string    `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	FromStatus   string    `gorm:"column:from_status;size:20" json:"fromStatus"`
+	ToStatus     string    `gorm:"column:to_status;size:20" json:"toStatus"`
+	Comment      string    `gorm:"column:comment;type:text" json:"comment"`
+	ActorID      string    `gorm:"column:actor_id;size:100" json:"actorId"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"createdAt"`
+}
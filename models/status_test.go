@@ -0,0 +1,29 @@
+package model
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	statuses := []string{StatusPendingReview, StatusApproved, StatusRejected, StatusNeedsChanges, StatusSuspended}
+	allowed := map[[2]string]bool{
+		{StatusPendingReview, StatusApproved}:     true,
+		{StatusPendingReview, StatusRejected}:     true,
+		{StatusPendingReview, StatusNeedsChanges}: true,
+		{StatusNeedsChanges, StatusPendingReview}: true,
+		{StatusNeedsChanges, StatusRejected}:      true,
+		{StatusApproved, StatusSuspended}:         true,
+		{StatusSuspended, StatusApproved}:         true,
+	}
+
+	// Every pair not listed is refused, including staying put and leaving rejected.
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]string{from, to}]
+			if got := CanTransition(from, to); got != want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+	if CanTransition("", StatusApproved) || CanTransition(StatusPendingReview, "archived") {
+		t.Error("unknown statuses must not transition")
+	}
+}
@@ -0,0 +1,62 @@
+package model
+
+// Tag kinds.
+const (
+	TagCuisine  = "cuisine"
+	TagDiet     = "diet"
+	TagAllergen = "allergen"
+)
+
+// Tag is an entry of a controlled vocabulary. ID is a stable slug such as
+// "vegan" or "south_indian" that clients filter on.
+type Tag struct {
+	ID    string `gorm:"column:id;size:50" json:"id"`
+	Kind  string `gorm:"column:kind;size:20;index" json:"kind"`
+	Label string `gorm:"column:label" json:"label"`
+}
+
+// RestaurantCuisine links a restaurant to a cuisine tag.
+type RestaurantCuisine struct {
+	RestaurantID string `gorm:"column:restaurant_id;size:100;primaryKey" json:"restaurantId"`
+	TagID        string `gorm:"column:tag_id;size:50;primaryKey;index" json:"tagId"`
+}
+
+// ProductTag links a product to a dietary or allergen tag.
+type ProductTag struct {
+	ProductID string `gorm:"column:product_id;size:50;primaryKey" json:"productId"`
+	TagID     string `gorm:"column:tag_id;size:50;primaryKey;index" json:"tagId"`
+}
+
+// ProductTagFilter selects products carrying every tag in Diets and none in
+// ExcludeAllergens. Category is optional.
+type ProductTagFilter struct {
+	Diets            []string
+	ExcludeAllergens []string
+	Category         string
+}
+
+// DefaultTags is the vocabulary seeded into a fresh database.
+var DefaultTags = []Tag{
+	{ID: "north_indian", Kind: TagCuisine, Label: "North Indian"},
+	{ID: "south_indian", Kind: TagCuisine, Label: "South Indian"},
+	{ID: "chinese", Kind: TagCuisine, Label: "Chinese"},
+	{ID: "italian", Kind: TagCuisine, Label: "Italian"},
+	{ID: "continental", Kind: TagCuisine, Label: "Continental"},
+	{ID: "mughlai", Kind: TagCuisine, Label: "Mughlai"},
+	{ID: "street_food", Kind: TagCuisine, Label: "Street Food"},
+	{ID: "desserts", Kind: TagCuisine, Label: "Desserts"},
+	{ID: "beverages", Kind: TagCuisine, Label: "Beverages"},
+
+	{ID: "veg", Kind: TagDiet, Label: "Vegetarian"},
+	{ID: "vegan", Kind: TagDiet, Label: "Vegan"},
+	{ID: "jain", Kind: TagDiet, Label: "Jain"},
+	{ID: "gluten_free", Kind: TagDiet, Label: "Gluten-free"},
+	{ID: "halal", Kind: TagDiet, Label: "Halal"},
+
+	{ID: "nuts", Kind: TagAllergen, Label: "Nuts"},
+	{ID: "dairy", Kind: TagAllergen, Label: "Dairy"},
+	{ID: "shellfish", Kind: TagAllergen, Label: "Shellfish"},
+	{ID: "gluten", Kind: TagAllergen, Label: "Gluten"},
+	{ID: "egg", Kind: TagAllergen, Label: "Egg"},
+	{ID: "soy", Kind: TagAllergen, Label: "Soy"},
+}
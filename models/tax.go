@@ -0,0 +1,100 @@
+package model
+
+import "sort"
+
+// TaxClass is a named tax rate defined by a restaurant, such as GST 5% for
+// restaurant service or GST 18% for packaged beverages.
+type TaxClass struct {
+	ID           string `gorm:"column:id;size:100" json:"id"`
+	RestaurantID string `gorm:"column:restaurant_id;size:100;index" json:"restaurantId"`
+	Name         string `gorm:"column:name" json:"name"`
+	// RateBasisPoints is the rate in hundredths of a percent: 500 is 5%.
+	RateBasisPoints int `gorm:"column:rate_bps" json:"rateBasisPoints"`
+}
+
+// LineItem is a product and quantity to be priced.
+type LineItem struct {
+	ProductID string `json:"productId"`
+	Quantity  int32  `json:"quantity"`
+}
+
+// QuoteLine is a priced line of a quote. LineTotal is UnitPrice x Quantity as
+// listed, which already includes tax for tax-inclusive products. Discount is
+// taken off LineTotal before tax is worked out.
+type QuoteLine struct {
+	ProductID       string `json:"productId"`
+	Name            string `json:"name"`
+	Quantity        int32  `json:"quantity"`
+	UnitPrice       Amount `json:"unitPrice"`
+	LineTotal       Amount `json:"lineTotal"`
+	Discount        Amount `json:"discount"`
+	TaxInclusive    bool   `json:"taxInclusive"`
+	RateBasisPoints int    `json:"rateBasisPoints"`
+}
+
+// TaxLine is the tax charged at one rate across the whole quote.
+type TaxLine struct {
+	RateBasisPoints int    `json:"rateBasisPoints"`
+	Taxable         Amount `json:"taxable"`
+	Tax             Amount `json:"tax"`
+}
+
+// PriceQuote is the invoice-ready breakdown of a cart. Subtotal excludes tax and
+// is net of discounts.
+type PriceQuote struct {
+	Currency      string              `json:"currency"`
+	Lines         []*QuoteLine        `json:"lines"`
+	DiscountTotal Amount              `json:"discountTotal"`
+	Subtotal      Amount              `json:"subtotal"`
+	Taxes         []*TaxLine          `json:"taxes"`
+	TaxTotal      Amount              `json:"taxTotal"`
+	Total         Amount              `json:"total"`
+	Promotions    []*AppliedPromotion `json:"promotions,omitempty"`
+	Skipped       []*SkippedPromotion `json:"skipped,omitempty"`
+}
+
+// ComputeTaxes fills Subtotal, Taxes, TaxTotal and Total from the quote lines.
+// Tax is rounded once per rate on the summed amounts rather than per line, so
+// the breakdown matches what an invoice for the same cart shows.
+func (q *PriceQuote) ComputeTaxes() {
+	type bucket struct{ exclusive, inclusive Amount }
+	buckets := map[int]*bucket{}
+	q.DiscountTotal = 0
+	for _, l := range q.Lines {
+		b, ok := buckets[l.RateBasisPoints]
+		if !ok {
+			b = &bucket{}
+			buckets[l.RateBasisPoints] = b
+		}
+		net := l.LineTotal - l.Discount
+		if l.TaxInclusive {
+			b.inclusive += net
+		} else {
+			b.exclusive += net
+		}
+		q.DiscountTotal += l.Discount
+	}
+
+	q.Subtotal, q.TaxTotal, q.Taxes = 0, 0, nil
+	for rate, b := range buckets {
+		exclusiveTax := roundDiv(b.exclusive*Amount(rate), 10000)
+		inclusiveNet := roundDiv(b.inclusive*10000, Amount(10000+rate))
+		line := &TaxLine{
+			RateBasisPoints: rate,
+			Taxable:         b.exclusive + inclusiveNet,
+			Tax:             exclusiveTax + (b.inclusive - inclusiveNet),
+		}
+		q.Taxes = append(q.Taxes, line)
+		q.Subtotal += line.Taxable
+		q.TaxTotal += line.Tax
+	}
+	sort.Slice(q.Taxes, func(i, j int) bool {
+		return q.Taxes[i].RateBasisPoints < q.Taxes[j].RateBasisPoints
+	})
+	q.Total = q.Subtotal + q.TaxTotal
+}
+
+// roundDiv divides non-negative amounts, rounding half up.
+func roundDiv(a, b Amount) Amount {
+	return (2*a + b) / (2 * b)
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeTaxes(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []*QuoteLine
+		want  PriceQuote
+	}{
+		{
+			name:  "empty cart",
+			lines: nil,
+			want:  PriceQuote{},
+		},
+		{
+			name:  "tax-exclusive",
+			lines: []*QuoteLine{{LineTotal: 10000, RateBasisPoints: 500}},
+			want: PriceQuote{
+				Subtotal: 10000, TaxTotal: 500, Total: 10500,
+				Taxes: []*TaxLine{{RateBasisPoints: 500, Taxable: 10000, Tax: 500}},
+			},
+		},
+		{
+			name:  "tax-inclusive",
+			lines: []*QuoteLine{{LineTotal: 11800, TaxInclusive: true, RateBasisPoints: 1800}},
+			want: PriceQuote{
+				Subtotal: 10000, TaxTotal: 1800, Total: 11800,
+				Taxes: []*TaxLine{{RateBasisPoints: 1800, Taxable: 10000, Tax: 1800}},
+			},
+		},
+		{
+			name: "mixed rates sorted by rate",
+			lines: []*QuoteLine{
+				{LineTotal: 11800, TaxInclusive: true, RateBasisPoints: 1800},
+				{LineTotal: 10000, RateBasisPoints: 500},
+			},
+			want: PriceQuote{
+				Subtotal: 20000, TaxTotal: 2300, Total: 22300,
+				Taxes: []*TaxLine{
+					{RateBasisPoints: 500, Taxable: 10000, Tax: 500},
+					{RateBasisPoints: 1800, Taxable: 10000, Tax: 1800},
+				},
+			},
+		},
+		{
+			name: "inclusive and exclusive at the same rate",
+			lines: []*QuoteLine{
+				{LineTotal: 10500, TaxInclusive: true, RateBasisPoints: 500},
+				{LineTotal: 10000, RateBasisPoints: 500},
+			},
+			want: PriceQuote{
+				Subtotal: 20000, TaxTotal: 1000, Total: 21000,
+				Taxes: []*TaxLine{{RateBasisPoints: 500, Taxable: 20000, Tax: 1000}},
+			},
+		},
+		{
+			name: "discounts come off before tax",
+			lines: []*QuoteLine{
+				{LineTotal: 10000, Discount: 2000, RateBasisPoints: 500},
+				{LineTotal: 5000, Discount: 1000, RateBasisPoints: 500},
+			},
+			want: PriceQuote{
+				DiscountTotal: 3000, Subtotal: 12000, TaxTotal: 600, Total: 12600,
+				Taxes: []*TaxLine{{RateBasisPoints: 500, Taxable: 12000, Tax: 600}},
+			},
+		},
+		{
+			// 2.5% of 130 is 3.25, which rounded per line would total 6.
+			name: "rounded once per rate",
+			lines: []*QuoteLine{
+				{LineTotal: 130, RateBasisPoints: 250},
+				{LineTotal: 130, RateBasisPoints: 250},
+			},
+			want: PriceQuote{
+				Subtotal: 260, TaxTotal: 7, Total: 267,
+				Taxes: []*TaxLine{{RateBasisPoints: 250, Taxable: 260, Tax: 7}},
+			},
+		},
+		{
+			name:  "half rounds up",
+			lines: []*QuoteLine{{LineTotal: 10, RateBasisPoints: 500}},
+			want: PriceQuote{
+				Subtotal: 10, TaxTotal: 1, Total: 11,
+				Taxes: []*TaxLine{{RateBasisPoints: 500, Taxable: 10, Tax: 1}},
+			},
+		},
+		{
+			name:  "zero-rated",
+			lines: []*QuoteLine{{LineTotal: 4000}},
+			want: PriceQuote{
+				Subtotal: 4000, Total: 4000,
+				Taxes: []*TaxLine{{Taxable: 4000}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &PriceQuote{Lines: tt.lines}
+			q.ComputeTaxes()
+			if q.DiscountTotal != tt.want.DiscountTotal || q.Subtotal != tt.want.Subtotal ||
+				q.TaxTotal != tt.want.TaxTotal || q.Total != tt.want.Total {
+				t.Errorf("totals = discount %d, subtotal %d, tax %d, total %d; want %d, %d, %d, %d",
+					q.DiscountTotal, q.Subtotal, q.TaxTotal, q.Total,
+					tt.want.DiscountTotal, tt.want.Subtotal, tt.want.TaxTotal, tt.want.Total)
+			}
+			if !reflect.DeepEqual(q.Taxes, tt.want.Taxes) {
+				t.Errorf("Taxes = %+v, want %+v", q.Taxes, tt.want.Taxes)
+			}
+		})
+	}
+}
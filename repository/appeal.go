@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Ban appeal operations
+// CreateBanAppeal files the appeal unless one is already pending for the ban. The
+// ban row is locked while checking, so two submissions for the same ban cannot
+// both get through.
+func (r *restaurantRepository) CreateBanAppeal(appeal *model.BanAppeal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ban model.BanRecord
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", appeal.BanID).First(&ban)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return model.ErrBanNotFound
+			}
+			return result.Error
+		}
+
+		var pending int64
+		if err := tx.Model(&model.BanAppeal{}).
+			Where("ban_id = ? AND status = ?", appeal.BanID, model.AppealPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return model.ErrAppealAlreadyPending
+		}
+
+		if err := tx.Create(appeal).Error; err != nil {
+			return fmt.Errorf("failed to create ban appeal: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *restaurantRepository) GetBanAppealByID(appealID string) (*model.BanAppeal, error) {
+	var appeal model.BanAppeal
+	result := r.db.Where("id = ?", appealID).First(&appeal)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrAppealNotFound
+		}
+		return nil, result.Error
+	}
+	return &appeal, nil
+}
+
+func (r *restaurantRepository) GetPendingAppealForBan(banID string) (*model.BanAppeal, error) {
+	var appeal model.BanAppeal
+	result := r.db.Where("ban_id = ? AND status = ?", banID, model.AppealPending).First(&appeal)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrAppealNotFound
+		}
+		return nil, result.Error
+	}
+	return &appeal, nil
+}
+
+// ListBanAppeals returns appeals oldest first, filtered by status unless it is empty.
+func (r *restaurantRepository) ListBanAppeals(status string) ([]*model.BanAppeal, error) {
+	var appeals []*model.BanAppeal
+	query := r.db.Order("created_at")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Find(&appeals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return appeals, nil
+}
+
+// ReviewBanAppeal records the decision on a pending appeal. It fails with
+// model.ErrAppealAlreadyReviewed if another reviewer got there first. Accepting
+// lifts the appealed ban with liftNote in the same transaction, so an appeal is
+// never marked accepted while its ban stays in force.
+func (r *restaurantRepository) ReviewBanAppeal(appealID, status, reviewerID, comment, liftNote string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var appeal model.BanAppeal
+		if err := tx.Where("id = ?", appealID).First(&appeal).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrAppealNotFound
+			}
+			return err
+		}
+
+		result := tx.Model(&model.BanAppeal{}).
+			Where("id = ? AND status = ?", appealID, model.AppealPending).
+			Updates(map[string]interface{}{
+				"status":         status,
+				"reviewer_id":    reviewerID,
+				"review_comment": comment,
+				"reviewed_at":    now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrAppealAlreadyReviewed
+		}
+
+		if status != model.AppealAccepted {
+			return nil
+		}
+		if err := liftBan(tx, appeal.BanID, reviewerID, liftNote, now); err != nil {
+			return fmt.Errorf("failed to lift ban: %v", err)
+		}
+		return nil
+	})
+}
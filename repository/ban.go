@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Ban operations
+
+// BanRestaurant flags the restaurant as banned and stores the ban record.
+func (r *restaurantRepository) BanRestaurant(ban *model.BanRecord) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return banRestaurant(tx, ban)
+	})
+}
+
+// banRestaurant does the work of BanRestaurant inside the caller's transaction.
+func banRestaurant(tx *gorm.DB, ban *model.BanRecord) error {
+	var restaurant model.Restaurant
+	if err := tx.Select("id").Where("id = ?", ban.RestaurantID).First(&restaurant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ErrRestaurantNotFound
+		}
+		return err
+	}
+
+	err := tx.Model(&model.Restaurant{}).
+		Where("id = ?", ban.RestaurantID).
+		Updates(map[string]interface{}{
+			"is_banned":  true,
+			"ban_reason": ban.Reason,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Create(ban).Error; err != nil {
+		return fmt.Errorf("failed to record ban: %v", err)
+	}
+	return nil
+}
+
+// UnbanRestaurant lifts every active ban of the restaurant and clears the flag.
+func (r *restaurantRepository) UnbanRestaurant(restaurantID, liftedBy, note string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var restaurant model.Restaurant
+		if err := tx.Select("id").Where("id = ?", restaurantID).First(&restaurant).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrRestaurantNotFound
+			}
+			return err
+		}
+
+		err := tx.Model(&model.BanRecord{}).
+			Where("restaurant_id = ? AND lifted_at IS NULL", restaurantID).
+			Updates(map[string]interface{}{
+				"lifted_at": now,
+				"lifted_by": liftedBy,
+				"lift_note": note,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to close ban records: %v", err)
+		}
+
+		return tx.Model(&model.Restaurant{}).
+			Where("id = ?", restaurantID).
+			Updates(map[string]interface{}{
+				"is_banned":  false,
+				"ban_reason": "",
+			}).Error
+	})
+}
+
+// LiftBan closes a single ban. The restaurant is only unflagged when no other
+// ban is still active; otherwise its reason falls back to the latest remaining ban.
+func (r *restaurantRepository) LiftBan(banID, liftedBy, note string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return liftBan(tx, banID, liftedBy, note, now)
+	})
+}
+
+// liftBan does the work of LiftBan inside the caller's transaction.
+func liftBan(tx *gorm.DB, banID, liftedBy, note string, now time.Time) error {
+	var ban model.BanRecord
+	if err := tx.Where("id = ?", banID).First(&ban).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ErrBanNotFound
+		}
+		return err
+	}
+	if ban.LiftedAt != nil {
+		return nil
+	}
+
+	err := tx.Model(&ban).Updates(map[string]interface{}{
+		"lifted_at": now,
+		"lifted_by": liftedBy,
+		"lift_note": note,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to close ban record: %v", err)
+	}
+
+	var remaining model.BanRecord
+	err = tx.Where("restaurant_id = ? AND lifted_at IS NULL", ban.RestaurantID).
+		Where("(ends_at IS NULL OR ends_at > ?)", now).
+		Order("starts_at DESC").
+		First(&remaining).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return tx.Model(&model.Restaurant{}).
+			Where("id = ?", ban.RestaurantID).
+			Updates(map[string]interface{}{
+				"is_banned":  false,
+				"ban_reason": "",
+			}).Error
+	case err != nil:
+		return err
+	default:
+		return tx.Model(&model.Restaurant{}).
+			Where("id = ?", ban.RestaurantID).
+			Update("ban_reason", remaining.Reason).Error
+	}
+}
+
+func (r *restaurantRepository) GetBanHistory(restaurantID string) ([]*model.BanRecord, error) {
+	var bans []*model.BanRecord
+	result := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("starts_at DESC").
+		Find(&bans)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return bans, nil
+}
+
+// GetExpiredBans returns timed bans whose end has passed but that were not lifted yet.
+func (r *restaurantRepository) GetExpiredBans(now time.Time) ([]*model.BanRecord, error) {
+	var bans []*model.BanRecord
+	result := r.db.Where("lifted_at IS NULL AND ends_at IS NOT NULL AND ends_at <= ?", now).
+		Find(&bans)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return bans, nil
+}
+
+func (r *restaurantRepository) GetBanByID(banID string) (*model.BanRecord, error) {
+	var ban model.BanRecord
+	result := r.db.Where("id = ?", banID).First(&ban)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrBanNotFound
+		}
+		return nil, result.Error
+	}
+	return &ban, nil
+}
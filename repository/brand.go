@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Brand operations
+func (r *restaurantRepository) CreateBrand(brand *model.Brand) error {
+	result := r.db.Create(brand)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create brand: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetBrandByID(brandID string) (*model.Brand, error) {
+	var brand model.Brand
+	result := r.db.Where("id = ?", brandID).First(&brand)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrBrandNotFound
+		}
+		return nil, result.Error
+	}
+	return &brand, nil
+}
+
+func (r *restaurantRepository) GetBrandByEmail(email string) (*model.Brand, error) {
+	var brand model.Brand
+	result := r.db.Where("owner_email = ?", email).First(&brand)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrBrandNotFound
+		}
+		return nil, result.Error
+	}
+	return &brand, nil
+}
+
+func (r *restaurantRepository) GetBranches(brandID string) ([]*model.Restaurant, error) {
+	var restaurants []*model.Restaurant
+	result := r.db.Where("brand_id = ?", brandID).Find(&restaurants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return restaurants, nil
+}
+
+// SetRestaurantBrand puts a standalone restaurant under a brand. It fails with
+// model.ErrAlreadyInBrand if the restaurant already belongs to one.
+func (r *restaurantRepository) SetRestaurantBrand(restaurantID, brandID string) error {
+	result := r.db.Model(&model.Restaurant{}).
+		Where("id = ? AND (brand_id IS NULL OR brand_id = '')", restaurantID).
+		Update("brand_id", brandID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to attach branch: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrAlreadyInBrand
+	}
+	return nil
+}
+
+func (r *restaurantRepository) AddBrandMenuItem(item *model.BrandMenuItem) error {
+	result := r.db.Create(item)
+	if result.Error != nil {
+		return fmt.Errorf("failed to add brand menu item: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetBrandMenu(brandID string) ([]*model.BrandMenuItem, error) {
+	var items []*model.BrandMenuItem
+	result := r.db.Where("brand_id = ?", brandID).Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+// SyncBranchProducts creates or refreshes branch products copied from a brand menu
+// in one transaction. An existing product is matched on restaurant and brand menu
+// item; its stock is left untouched, and a changed listing is recorded in its
+// history under actorID like any other edit.
+func (r *restaurantRepository) SyncBranchProducts(products []*model.Product, actorID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, p := range products {
+			var existing model.Product
+			err := tx.Where("restaurant_id = ? AND brand_menu_item_id = ?", p.RestaurantID, p.BrandMenuItemID).
+				First(&existing).Error
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if err := tx.Create(p).Error; err != nil {
+					return fmt.Errorf("failed to add branch product: %v", err)
+				}
+			case err != nil:
+				return err
+			default:
+				if existing.Name == p.Name && existing.Description == p.Description &&
+					existing.Price == p.Price && existing.Category == p.Category {
+					continue
+				}
+				existing.Name = p.Name
+				existing.Description = p.Description
+				existing.Price = p.Price
+				existing.Category = p.Category
+				if err := updateProductWithHistory(tx, &existing, actorID, "brand menu sync"); err != nil {
+					return fmt.Errorf("failed to update branch product: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+}
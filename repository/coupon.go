@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Coupon operations
+func (r *restaurantRepository) CreateCoupon(coupon *model.Coupon) error {
+	result := r.db.Create(coupon)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create coupon: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetCouponByCode(restaurantID, code string) (*model.Coupon, error) {
+	var coupon model.Coupon
+	result := r.db.Where("restaurant_id = ? AND code = ?", restaurantID, code).First(&coupon)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrCouponNotFound
+		}
+		return nil, result.Error
+	}
+	return &coupon, nil
+}
+
+func (r *restaurantRepository) CountCouponRedemptions(couponID, customerID string) (int64, error) {
+	var count int64
+	result := r.db.Model(&model.CouponRedemption{}).
+		Where("coupon_id = ? AND customer_id = ?", couponID, customerID).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// RedeemCoupon records a redemption while enforcing both caps atomically. The
+// coupon row is locked for the duration of the transaction, so concurrent
+// checkouts for the same coupon are serialised and the global counter is bumped
+// with a guarded update that cannot pass the cap. The coupon is checked again on
+// the locked row, so one deactivated or expired since the caller read it is
+// refused, and the discount is recomputed from it.
+func (r *restaurantRepository) RedeemCoupon(coupon *model.Coupon, redemption *model.CouponRedemption, orderTotal model.Amount, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked model.Coupon
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", coupon.ID).
+			First(&locked).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrCouponNotFound
+			}
+			return err
+		}
+		if err := locked.Check(orderTotal, now); err != nil {
+			return err
+		}
+		redemption.Discount = locked.Discount(orderTotal)
+
+		var existing int64
+		if err := tx.Model(&model.CouponRedemption{}).Where("order_id = ?", redemption.OrderID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return model.ErrCouponAlreadyRedeemed
+		}
+
+		if locked.PerUserCap > 0 {
+			var used int64
+			err := tx.Model(&model.CouponRedemption{}).
+				Where("coupon_id = ? AND customer_id = ?", coupon.ID, redemption.CustomerID).
+				Count(&used).Error
+			if err != nil {
+				return err
+			}
+			if used >= int64(locked.PerUserCap) {
+				return model.ErrCouponUserLimit
+			}
+		}
+
+		result := tx.Model(&model.Coupon{}).
+			Where("id = ? AND (usage_cap = 0 OR redemptions < usage_cap)", coupon.ID).
+			Update("redemptions", gorm.Expr("redemptions + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrCouponExhausted
+		}
+
+		if err := tx.Create(redemption).Error; err != nil {
+			return fmt.Errorf("failed to record coupon redemption: %v", err)
+		}
+		return nil
+	})
+}
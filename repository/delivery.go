@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"fmt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// Delivery zone operations
+func (r *restaurantRepository) AddDeliveryZone(zone *model.DeliveryZone) error {
+	result := r.db.Create(zone)
+	if result.Error != nil {
+		return fmt.Errorf("failed to add delivery zone: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetDeliveryZones(restaurantID string) ([]*model.DeliveryZone, error) {
+	var zones []*model.DeliveryZone
+	result := r.db.Where("restaurant_id = ?", restaurantID).Find(&zones)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return zones, nil
+}
+
+func (r *restaurantRepository) DeleteDeliveryZone(restaurantID, zoneID string) error {
+	result := r.db.Delete(&model.DeliveryZone{}, "id = ? AND restaurant_id = ?", zoneID, restaurantID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrDeliveryZoneNotFound
+	}
+	return nil
+}
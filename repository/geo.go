@@ -0,0 +1,36 @@
+package repository
+
+import (
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Geolocation operations
+func (r *restaurantRepository) UpdateRestaurantLocation(restaurantID string, lat, lng float64) error {
+	result := r.db.Model(&model.Restaurant{}).
+		Where("id = ?", restaurantID).
+		Updates(map[string]interface{}{
+			"latitude":  lat,
+			"longitude": lng,
+			"version":   gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetRestaurantsInBoundingBox(box model.BoundingBox) ([]*model.Restaurant, error) {
+	var restaurants []*model.Restaurant
+	result := r.db.
+		Scopes(visibleRestaurants).
+		Where("latitude BETWEEN ? AND ?", box.MinLat, box.MaxLat).
+		Where("longitude BETWEEN ? AND ?", box.MinLng, box.MaxLng).
+		// Rows that never had a location saved sit at (0, 0) and must not match.
+		Where("NOT (latitude = 0 AND longitude = 0)").
+		Find(&restaurants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return restaurants, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Product history operations
+
+// UpdateProductWithHistory saves the product's listing fields and records the new
+// state as the next history version, in one transaction. A product edited for the
+// first time gets its stored state recorded as version 1 beforehand, dated to when
+// it was created, so the original values are never lost. product.Version must be
+// the row version the caller read; a stale one fails with model.ErrStaleVersion.
+func (r *restaurantRepository) UpdateProductWithHistory(product *model.Product, actorID, note string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return updateProductWithHistory(tx, product, actorID, note)
+	})
+}
+
+// updateProductWithHistory does the work of UpdateProductWithHistory inside the
+// caller's transaction.
+func updateProductWithHistory(tx *gorm.DB, product *model.Product, actorID, note string) error {
+	// Locking the product row serialises concurrent edits, so two of them cannot
+	// both read the same latest history version and number theirs alike.
+	var stored model.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", product.ID).First(&stored).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ErrProductNotFound
+		}
+		return err
+	}
+	if stored.Version != product.Version {
+		return model.ErrStaleVersion
+	}
+
+	var latest int
+	err = tx.Model(&model.ProductVersion{}).
+		Where("product_id = ?", product.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	if latest == 0 {
+		original := model.NewProductVersion(&stored, "", "original")
+		original.Version = 1
+		original.CreatedAt = stored.CreatedAt
+		if err := tx.Create(original).Error; err != nil {
+			return fmt.Errorf("failed to record product version: %v", err)
+		}
+		latest = 1
+	}
+
+	// Only the listing fields are written; stock and rating aggregates change
+	// concurrently through their own paths and do not bump the version.
+	result := tx.Model(&model.Product{}).
+		Where("id = ? AND version = ?", product.ID, product.Version).
+		Updates(map[string]interface{}{
+			"name":        product.Name,
+			"description": product.Description,
+			"price_minor": product.Price,
+			"category":    product.Category,
+			"version":     gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update product: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrStaleVersion
+	}
+	product.Version++
+
+	version := model.NewProductVersion(product, actorID, note)
+	version.Version = latest + 1
+	if err := tx.Create(version).Error; err != nil {
+		return fmt.Errorf("failed to record product version: %v", err)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetProductHistory(productID string) ([]*model.ProductVersion, error) {
+	var versions []*model.ProductVersion
+	result := r.db.Where("product_id = ?", productID).Order("version DESC").Find(&versions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return versions, nil
+}
+
+func (r *restaurantRepository) GetProductVersion(productID string, version int) (*model.ProductVersion, error) {
+	var v model.ProductVersion
+	result := r.db.Where("product_id = ? AND version = ?", productID, version).First(&v)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrProductVersionNotFound
+		}
+		return nil, result.Error
+	}
+	return &v, nil
+}
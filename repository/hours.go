@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"fmt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Opening hours operations
+func (r *restaurantRepository) SetOpeningHours(restaurantID string, hours []*model.OpeningHours) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("restaurant_id = ?", restaurantID).Delete(&model.OpeningHours{}).Error; err != nil {
+			return fmt.Errorf("failed to clear opening hours: %v", err)
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		for _, h := range hours {
+			h.ID = 0
+			h.RestaurantID = restaurantID
+		}
+		if err := tx.Create(&hours).Error; err != nil {
+			return fmt.Errorf("failed to save opening hours: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *restaurantRepository) GetOpeningHours(restaurantID string) ([]*model.OpeningHours, error) {
+	var hours []*model.OpeningHours
+	result := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("weekday, opens_at").
+		Find(&hours)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return hours, nil
+}
+
+// GetOpeningHoursByRestaurantIDs loads the hours of several restaurants in one
+// query, grouped by restaurant ID.
+func (r *restaurantRepository) GetOpeningHoursByRestaurantIDs(restaurantIDs []string) (map[string][]*model.OpeningHours, error) {
+	grouped := make(map[string][]*model.OpeningHours, len(restaurantIDs))
+	if len(restaurantIDs) == 0 {
+		return grouped, nil
+	}
+
+	var hours []*model.OpeningHours
+	result := r.db.Where("restaurant_id IN ?", restaurantIDs).
+		Order("weekday, opens_at").
+		Find(&hours)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, h := range hours {
+		grouped[h.RestaurantID] = append(grouped[h.RestaurantID], h)
+	}
+	return grouped, nil
+}
+
+func (r *restaurantRepository) AddHolidayClosure(closure *model.HolidayClosure) error {
+	result := r.db.Create(closure)
+	if result.Error != nil {
+		return fmt.Errorf("failed to add holiday closure: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) DeleteHolidayClosure(restaurantID, date string) error {
+	result := r.db.Where("restaurant_id = ? AND date = ?", restaurantID, date).
+		Delete(&model.HolidayClosure{})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetHolidayClosures(restaurantID string) ([]*model.HolidayClosure, error) {
+	var closures []*model.HolidayClosure
+	result := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("date").
+		Find(&closures)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return closures, nil
+}
+
+// GetHolidayClosuresByRestaurantIDs loads the closures of several restaurants in
+// one query, grouped by restaurant ID.
+func (r *restaurantRepository) GetHolidayClosuresByRestaurantIDs(restaurantIDs []string) (map[string][]*model.HolidayClosure, error) {
+	grouped := make(map[string][]*model.HolidayClosure, len(restaurantIDs))
+	if len(restaurantIDs) == 0 {
+		return grouped, nil
+	}
+
+	var closures []*model.HolidayClosure
+	result := r.db.Where("restaurant_id IN ?", restaurantIDs).
+		Order("date").
+		Find(&closures)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, c := range closures {
+		grouped[c.RestaurantID] = append(grouped[c.RestaurantID], c)
+	}
+	return grouped, nil
+}
+
+func (r *restaurantRepository) SetRestaurantTimezone(restaurantID, timezone string) error {
+	result := r.db.Model(&model.Restaurant{}).
+		Where("id = ?", restaurantID).
+		Updates(map[string]interface{}{
+			"timezone": timezone,
+			"version":  gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *restaurantRepository) SetRestaurantPaused(restaurantID string, paused bool) error {
+	result := r.db.Model(&model.Restaurant{}).
+		Where("id = ?", restaurantID).
+		Updates(map[string]interface{}{
+			"is_paused": paused,
+			"version":   gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrRestaurantNotFound
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Bulk import operations
+
+// errRollback rolls back the import transaction once a dry run, or an import
+// with conflicting rows, has been counted.
+var errRollback = errors.New("import rolled back")
+
+// ImportProducts upserts the restaurant's products in one transaction. A row
+// matches an existing product by SKU, or by name among products without a SKU.
+// Matched products whose listing changed are updated through the product
+// history; SKU and stock changes alone are written directly. Other rows create
+// products. A SKU still held by a deleted product is reported as an error on the
+// row, and the import is then rolled back. With dryRun set everything runs and
+// is then rolled back, so the counts are exactly what a real import would do.
+func (r *restaurantRepository) ImportProducts(restaurantID string, items []*model.ImportedProduct, actorID string, dryRun bool) (*model.ImportResult, error) {
+	result := &model.ImportResult{DryRun: dryRun}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Deleted products keep their SKU, so they are loaded too to report clashes
+		var existing []*model.Product
+		if err := tx.Unscoped().Where("restaurant_id = ?", restaurantID).Find(&existing).Error; err != nil {
+			return err
+		}
+		bySKU := make(map[string]*model.Product, len(existing))
+		byName := make(map[string]*model.Product, len(existing))
+		for _, p := range existing {
+			switch {
+			case p.SKUCode() != "":
+				bySKU[p.SKUCode()] = p
+			case !p.DeletedAt.Valid:
+				byName[strings.ToLower(p.Name)] = p
+			}
+		}
+
+		for _, item := range items {
+			product := bySKU[item.SKU]
+			if product != nil && product.DeletedAt.Valid {
+				result.Errors = append(result.Errors, model.ImportError{
+					Line:    item.Line,
+					Message: fmt.Sprintf("sku %q belongs to a deleted product; restore it first", item.SKU),
+				})
+				continue
+			}
+			if product == nil {
+				// A product matched by name is claimed so no later row matches it again
+				key := strings.ToLower(item.Name)
+				product = byName[key]
+				delete(byName, key)
+			}
+
+			if product == nil {
+				product = &model.Product{
+					ID:           fmt.Sprintf("prod_%s", uuid.New().String()),
+					RestaurantID: restaurantID,
+					CreatedBy:    actorID,
+					UpdatedBy:    actorID,
+				}
+				item.ApplyTo(product)
+				if err := tx.Create(product).Error; err != nil {
+					return fmt.Errorf("line %d: failed to add product: %v", item.Line, err)
+				}
+				result.Created++
+				continue
+			}
+
+			listing, inventory := item.ApplyTo(product)
+			if !listing && !inventory {
+				result.Unchanged++
+				continue
+			}
+			// The listing goes first so a product's first history entry still
+			// records its stock from before the import
+			if listing {
+				if err := updateProductWithHistory(tx, product, actorID, "import"); err != nil {
+					return fmt.Errorf("line %d: %w", item.Line, err)
+				}
+			}
+			if inventory {
+				err := tx.Model(&model.Product{}).
+					Where("id = ?", product.ID).
+					Updates(map[string]interface{}{"sku": product.SKU, "stock": product.Stock}).Error
+				if err != nil {
+					return fmt.Errorf("line %d: failed to update product: %v", item.Line, err)
+				}
+			}
+			result.Updated++
+		}
+
+		if dryRun || len(result.Errors) > 0 {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, err
+	}
+	return result, nil
+}
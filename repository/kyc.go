@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// KYC document operations
+func (r *restaurantRepository) CreateKYCDocument(doc *model.KYCDocument) error {
+	result := r.db.Create(doc)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create KYC document: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetKYCDocumentByID(docID string) (*model.KYCDocument, error) {
+	var doc model.KYCDocument
+	result := r.db.Where("id = ?", docID).First(&doc)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrDocumentNotFound
+		}
+		return nil, result.Error
+	}
+	return &doc, nil
+}
+
+func (r *restaurantRepository) GetKYCDocuments(restaurantID string) ([]*model.KYCDocument, error) {
+	var docs []*model.KYCDocument
+	result := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("created_at DESC").
+		Find(&docs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return docs, nil
+}
+
+// ReviewKYCDocument verifies or rejects a pending document.
+func (r *restaurantRepository) ReviewKYCDocument(docID, status, reviewerID, comment string, now time.Time) error {
+	result := r.db.Model(&model.KYCDocument{}).
+		Where("id = ? AND status = ?", docID, model.DocPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"reviewer_id":    reviewerID,
+			"review_comment": comment,
+			"reviewed_at":    now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrDocumentReviewed
+	}
+	return nil
+}
+
+// GetExpiringKYCDocuments returns verified documents expiring before the given
+// time whose owners have not been warned yet.
+func (r *restaurantRepository) GetExpiringKYCDocuments(before time.Time) ([]*model.KYCDocument, error) {
+	var docs []*model.KYCDocument
+	result := r.db.Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ? AND expiry_notified_at IS NULL",
+		model.DocVerified, before).
+		Find(&docs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return docs, nil
+}
+
+func (r *restaurantRepository) MarkKYCExpiryNotified(docID string, now time.Time) error {
+	return r.db.Model(&model.KYCDocument{}).
+		Where("id = ?", docID).
+		Update("expiry_notified_at", now).Error
+}
+
+// GetExpiredKYCDocuments returns verified documents whose expiry has passed.
+func (r *restaurantRepository) GetExpiredKYCDocuments(now time.Time) ([]*model.KYCDocument, error) {
+	var docs []*model.KYCDocument
+	result := r.db.Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", model.DocVerified, now).
+		Find(&docs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return docs, nil
+}
+
+// HasValidKYCDocument reports whether the restaurant has a verified document of
+// the type, other than excludeID, that is still valid after the given time.
+func (r *restaurantRepository) HasValidKYCDocument(restaurantID, docType, excludeID string, after time.Time) (bool, error) {
+	var count int64
+	result := r.db.Model(&model.KYCDocument{}).
+		Where("restaurant_id = ? AND doc_type = ? AND id <> ? AND status = ?", restaurantID, docType, excludeID, model.DocVerified).
+		Where("expires_at IS NULL OR expires_at > ?", after).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// ExpireKYCDocument marks a verified document as expired. A non-nil ban is
+// applied in the same transaction, so a failed suspension leaves the document
+// verified and the next run retries both.
+func (r *restaurantRepository) ExpireKYCDocument(docID string, ban *model.BanRecord) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.KYCDocument{}).
+			Where("id = ? AND status = ?", docID, model.DocVerified).
+			Update("status", model.DocExpired).Error
+		if err != nil {
+			return err
+		}
+		if ban == nil {
+			return nil
+		}
+		return banRestaurant(tx, ban)
+	})
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Currency operations
+
+// GetRestaurantCurrencies maps each restaurant ID to its currency code.
+func (r *restaurantRepository) GetRestaurantCurrencies(restaurantIDs []string) (map[string]string, error) {
+	currencies := make(map[string]string, len(restaurantIDs))
+	if len(restaurantIDs) == 0 {
+		return currencies, nil
+	}
+
+	var restaurants []*model.Restaurant
+	result := r.db.Select("id", "currency").Where("id IN ?", restaurantIDs).Find(&restaurants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, rest := range restaurants {
+		currencies[rest.ID] = rest.CurrencyCode()
+	}
+	return currencies, nil
+}
+
+// SetRestaurantCurrency changes the restaurant's currency, failing with
+// model.ErrCurrencyChange while it has any product, deleted ones included since
+// they can be restored with their old prices. The restaurant row stays locked
+// from the check to the update, so AddProduct cannot slip a product in between.
+func (r *restaurantRepository) SetRestaurantCurrency(restaurantID, currency string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var restaurant model.Restaurant
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", restaurantID).
+			First(&restaurant).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrRestaurantNotFound
+			}
+			return err
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&model.Product{}).Where("restaurant_id = ?", restaurantID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return model.ErrCurrencyChange
+		}
+
+		return tx.Model(&model.Restaurant{}).
+			Where("id = ?", restaurantID).
+			Updates(map[string]interface{}{
+				"currency": currency,
+				"version":  gorm.Expr("version + 1"),
+			}).Error
+	})
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"strconv"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Paginated listing operations. Pages use keyset pagination on (sort key, id):
+// the page token holds the last row's key and ID, and the next page starts
+// strictly after it, so pages stay stable while rows are inserted.
+
+// sortKey describes how a sort order maps to a column and how a row's value of
+// that column is written into and read back from a page token.
+type sortKey struct {
+	column string
+	desc   bool
+	encode func(row interface{}) string
+	decode func(key string) (interface{}, error)
+}
+
+func decodeString(key string) (interface{}, error) { return key, nil }
+
+func decodeInt(key string) (interface{}, error) { return strconv.ParseInt(key, 10, 64) }
+
+func decodeTime(key string) (interface{}, error) { return time.Parse(time.RFC3339Nano, key) }
+
+var productSortKeys = map[string]sortKey{
+	model.SortName: {"products.name", false,
+		func(row interface{}) string { return row.(*model.Product).Name }, decodeString},
+	model.SortPriceAsc: {"products.price_minor", false,
+		func(row interface{}) string { return strconv.FormatInt(int64(row.(*model.Product).Price), 10) }, decodeInt},
+	model.SortPriceDesc: {"products.price_minor", true,
+		func(row interface{}) string { return strconv.FormatInt(int64(row.(*model.Product).Price), 10) }, decodeInt},
+	model.SortNewest: {"products.created_at", true,
+		func(row interface{}) string { return row.(*model.Product).CreatedAt.Format(time.RFC3339Nano) }, decodeTime},
+}
+
+var restaurantSortKeys = map[string]sortKey{
+	model.SortName: {"restaurants.name", false,
+		func(row interface{}) string { return row.(*model.Restaurant).Name }, decodeString},
+	model.SortNewest: {"restaurants.created_at", true,
+		func(row interface{}) string { return row.(*model.Restaurant).CreatedAt.Format(time.RFC3339Nano) }, decodeTime},
+}
+
+// keysetPage orders the query by key and id and, when a token is given, starts
+// after the row it points to. idColumn is the table-qualified primary key.
+func keysetPage(query *gorm.DB, key sortKey, idColumn, sort, token string, pageSize int) (*gorm.DB, error) {
+	dir, cmp := "ASC", ">"
+	if key.desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	if token != "" {
+		cursor, err := model.DecodePageToken(token, sort)
+		if err != nil {
+			return nil, err
+		}
+		value, err := key.decode(cursor.Key)
+		if err != nil {
+			return nil, model.ErrInvalidPageToken
+		}
+		query = query.Where("("+key.column+" "+cmp+" ? OR ("+key.column+" = ? AND "+idColumn+" "+cmp+" ?))",
+			value, value, cursor.ID)
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	return query.Order(key.column + " " + dir).Order(idColumn + " " + dir).Limit(pageSize + 1), nil
+}
+
+func (r *restaurantRepository) ListProducts(opts model.ProductListOptions) (*model.ProductPage, error) {
+	key, ok := productSortKeys[opts.Sort]
+	if !ok {
+		return nil, model.ErrInvalidSort
+	}
+
+	query := r.db.Model(&model.Product{}).
+		Select("products.*").
+		Joins("JOIN restaurants ON restaurants.id = products.restaurant_id").
+		Scopes(pincodeFilter(opts.Pincode))
+	if opts.RestaurantID != "" {
+		// A single menu is listed like GetProductsByRestaurantID did, whatever the
+		// restaurant's review status, as long as it is not banned
+		query = query.Where("products.restaurant_id = ? AND restaurants.is_banned = ?", opts.RestaurantID, false)
+	} else {
+		query = query.Scopes(visibleRestaurants)
+	}
+	if opts.Category != "" {
+		query = query.Where("products.category = ?", opts.Category)
+	}
+	if opts.MinPrice > 0 {
+		query = query.Where("products.price_minor >= ?", opts.MinPrice)
+	}
+	if opts.MaxPrice > 0 {
+		query = query.Where("products.price_minor <= ?", opts.MaxPrice)
+	}
+	if opts.InStockOnly {
+		query = query.Where("products.stock > 0")
+	}
+	if opts.Locality != "" {
+		query = query.Where("restaurants.locality = ?", opts.Locality)
+	}
+
+	query, err := keysetPage(query, key, "products.id", opts.Sort, opts.PageToken, opts.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []*model.Product
+	if err := query.Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	page := &model.ProductPage{Products: products}
+	if len(products) > opts.PageSize {
+		page.Products = products[:opts.PageSize]
+		last := page.Products[len(page.Products)-1]
+		page.NextPageToken = model.EncodePageToken(model.PageCursor{Sort: opts.Sort, Key: key.encode(last), ID: last.ID})
+	}
+	return page, nil
+}
+
+func (r *restaurantRepository) ListRestaurants(opts model.RestaurantListOptions) (*model.RestaurantPage, error) {
+	key, ok := restaurantSortKeys[opts.Sort]
+	if !ok {
+		return nil, model.ErrInvalidSort
+	}
+
+	query := r.db.Model(&model.Restaurant{}).
+		Scopes(visibleRestaurants, pincodeFilter(opts.Pincode))
+	if opts.Locality != "" {
+		query = query.Where("restaurants.locality = ?", opts.Locality)
+	}
+
+	query, err := keysetPage(query, key, "restaurants.id", opts.Sort, opts.PageToken, opts.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var restaurants []*model.Restaurant
+	if err := query.Find(&restaurants).Error; err != nil {
+		return nil, err
+	}
+
+	page := &model.RestaurantPage{Restaurants: restaurants}
+	if len(restaurants) > opts.PageSize {
+		page.Restaurants = restaurants[:opts.PageSize]
+		last := page.Restaurants[len(page.Restaurants)-1]
+		page.NextPageToken = model.EncodePageToken(model.PageCursor{Sort: opts.Sort, Key: key.encode(last), ID: last.ID})
+	}
+	return page, nil
+}
+
+// GetProductsByRestaurantIDs loads the products of several restaurants in one
+// query, grouped by restaurant ID.
+func (r *restaurantRepository) GetProductsByRestaurantIDs(restaurantIDs []string) (map[string][]*model.Product, error) {
+	grouped := make(map[string][]*model.Product, len(restaurantIDs))
+	if len(restaurantIDs) == 0 {
+		return grouped, nil
+	}
+
+	var products []*model.Product
+	result := r.db.Where("restaurant_id IN ?", restaurantIDs).Find(&products)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, p := range products {
+		grouped[p.RestaurantID] = append(grouped[p.RestaurantID], p)
+	}
+	return grouped, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Price schedule operations
+func (r *restaurantRepository) CreatePriceSchedule(schedule *model.PriceSchedule) error {
+	result := r.db.Create(schedule)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create price schedule: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetPriceScheduleByID(scheduleID uint) (*model.PriceSchedule, error) {
+	var schedule model.PriceSchedule
+	result := r.db.Where("id = ?", scheduleID).First(&schedule)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrPriceScheduleNotFound
+		}
+		return nil, result.Error
+	}
+	return &schedule, nil
+}
+
+func (r *restaurantRepository) GetPriceSchedules(productID string) ([]*model.PriceSchedule, error) {
+	var schedules []*model.PriceSchedule
+	result := r.db.Where("product_id = ?", productID).Order("starts_at").Find(&schedules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return schedules, nil
+}
+
+func (r *restaurantRepository) DeletePriceSchedule(scheduleID uint) error {
+	result := r.db.Delete(&model.PriceSchedule{}, "id = ?", scheduleID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrPriceScheduleNotFound
+	}
+	return nil
+}
+
+// GetCurrentPriceSchedules maps each product ID to its schedules that have started
+// and not yet ended at now. Whether a recurring one is inside its daily window
+// depends on the restaurant's timezone and is left to the caller.
+func (r *restaurantRepository) GetCurrentPriceSchedules(productIDs []string, now time.Time) (map[string][]*model.PriceSchedule, error) {
+	byProduct := make(map[string][]*model.PriceSchedule)
+	if len(productIDs) == 0 {
+		return byProduct, nil
+	}
+
+	var schedules []*model.PriceSchedule
+	result := r.db.
+		Where("product_id IN ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", productIDs, now, now).
+		Find(&schedules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, s := range schedules {
+		byProduct[s.ProductID] = append(byProduct[s.ProductID], s)
+	}
+	return byProduct, nil
+}
+
+// GetRestaurantTimezones maps each restaurant ID to its timezone name, which is
+// empty for restaurants still on the default.
+func (r *restaurantRepository) GetRestaurantTimezones(restaurantIDs []string) (map[string]string, error) {
+	timezones := make(map[string]string, len(restaurantIDs))
+	if len(restaurantIDs) == 0 {
+		return timezones, nil
+	}
+
+	var restaurants []*model.Restaurant
+	result := r.db.Select("id", "timezone").Where("id IN ?", restaurantIDs).Find(&restaurants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, rest := range restaurants {
+		timezones[rest.ID] = rest.Timezone
+	}
+	return timezones, nil
+}
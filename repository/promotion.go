@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Promotion operations
+func (r *restaurantRepository) CreatePromotion(promotion *model.Promotion) error {
+	result := r.db.Create(promotion)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create promotion: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetPromotionByID(promotionID string) (*model.Promotion, error) {
+	var promotion model.Promotion
+	result := r.db.Where("id = ?", promotionID).First(&promotion)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrPromotionNotFound
+		}
+		return nil, result.Error
+	}
+	return &promotion, nil
+}
+
+// GetPromotions lists a restaurant's promotions. With activeOnly set, switched-off
+// and already-ended promotions are left out.
+func (r *restaurantRepository) GetPromotions(restaurantID string, activeOnly bool) ([]*model.Promotion, error) {
+	var promotions []*model.Promotion
+	query := r.db.Where("restaurant_id = ?", restaurantID)
+	if activeOnly {
+		query = query.Where("active = ? AND (ends_at IS NULL OR ends_at > ?)", true, time.Now())
+	}
+	result := query.Order("starts_at").Find(&promotions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return promotions, nil
+}
+
+func (r *restaurantRepository) SetPromotionActive(promotionID string, active bool) error {
+	result := r.db.Model(&model.Promotion{}).
+		Where("id = ?", promotionID).
+		Update("active", active)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// CountPromotionUsage returns how many times the customer has used each promotion.
+func (r *restaurantRepository) CountPromotionUsage(promotionIDs []string, customerID string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(promotionIDs))
+	if len(promotionIDs) == 0 || customerID == "" {
+		return counts, nil
+	}
+
+	var rows []struct {
+		PromotionID string
+		Uses        int64
+	}
+	result := r.db.Model(&model.PromotionUsage{}).
+		Select("promotion_id, COUNT(*) AS uses").
+		Where("promotion_id IN ? AND customer_id = ?", promotionIDs, customerID).
+		Group("promotion_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		counts[row.PromotionID] = row.Uses
+	}
+	return counts, nil
+}
+
+// RecordPromotionUsage stores the usages in one transaction, checking each
+// promotion's per-customer limit under a row lock so concurrent checkouts cannot
+// both take the last use. Limited promotions need the customer to be known. A
+// promotion already recorded for the order is skipped, so a retried checkout
+// does not count twice.
+func (r *restaurantRepository) RecordPromotionUsage(usages []*model.PromotionUsage) error {
+	if len(usages) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids := make([]string, 0, len(usages))
+		for _, u := range usages {
+			ids = append(ids, u.PromotionID)
+		}
+
+		// Locking in ID order keeps two checkouts from deadlocking on each other
+		var promotions []*model.Promotion
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("id").
+			Find(&promotions).Error
+		if err != nil {
+			return err
+		}
+		byID := make(map[string]*model.Promotion, len(promotions))
+		for _, p := range promotions {
+			byID[p.ID] = p
+		}
+
+		fresh := make([]*model.PromotionUsage, 0, len(usages))
+		for _, u := range usages {
+			promotion, ok := byID[u.PromotionID]
+			if !ok {
+				return model.ErrPromotionNotFound
+			}
+
+			var recorded int64
+			err := tx.Model(&model.PromotionUsage{}).
+				Where("promotion_id = ? AND order_id = ?", u.PromotionID, u.OrderID).
+				Count(&recorded).Error
+			if err != nil {
+				return err
+			}
+			if recorded > 0 {
+				continue
+			}
+			fresh = append(fresh, u)
+
+			if promotion.PerCustomerLimit == 0 {
+				continue
+			}
+			if u.CustomerID == "" {
+				return model.ErrPromotionNeedsCustomer
+			}
+
+			var used int64
+			err = tx.Model(&model.PromotionUsage{}).
+				Where("promotion_id = ? AND customer_id = ?", u.PromotionID, u.CustomerID).
+				Count(&used).Error
+			if err != nil {
+				return err
+			}
+			if used >= int64(promotion.PerCustomerLimit) {
+				return model.ErrPromotionLimitReached
+			}
+		}
+
+		if len(fresh) == 0 {
+			return nil
+		}
+		// The unique index on (promotion_id, order_id) backs up the check above
+		if err := tx.Create(&fresh).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return nil
+			}
+			return fmt.Errorf("failed to record promotion usage: %v", err)
+		}
+		return nil
+	})
+}
@@ -1,42 +1,167 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RestaurantRepository interface {
+	// WithContext returns a repository whose queries run with ctx, so the audit
+	// hooks can attribute writes to the actor it carries.
+	WithContext(ctx context.Context) RestaurantRepository
+
 	CreateRestaurant(restaurant *model.Restaurant) error
 	GetRestaurantByEmail(email string) (*model.Restaurant, error)
 	GetRestaurantByID(id string) (*model.Restaurant, error)
-	UpdateRestaurant(restaurant *model.Restaurant) error
+	UpdateRestaurantProfile(restaurant *model.Restaurant) error
 	GetAllRestaurants() ([]*model.Restaurant, error)
-	BanRestaurant(restaurantID, reason string) error
-	UnbanRestaurant(restaurantID string) error
+	GetVisibleRestaurants() ([]*model.Restaurant, error)
+	BanRestaurant(ban *model.BanRecord) error
+	UnbanRestaurant(restaurantID, liftedBy, note string, now time.Time) error
+	LiftBan(banID, liftedBy, note string, now time.Time) error
+	GetBanHistory(restaurantID string) ([]*model.BanRecord, error)
+	GetExpiredBans(now time.Time) ([]*model.BanRecord, error)
+	GetBanByID(banID string) (*model.BanRecord, error)
+
+	CreateBanAppeal(appeal *model.BanAppeal) error
+	GetBanAppealByID(appealID string) (*model.BanAppeal, error)
+	GetPendingAppealForBan(banID string) (*model.BanAppeal, error)
+	ListBanAppeals(status string) ([]*model.BanAppeal, error)
+	ReviewBanAppeal(appealID, status, reviewerID, comment, liftNote string, now time.Time) error
+
+	CreateKYCDocument(doc *model.KYCDocument) error
+	GetKYCDocumentByID(docID string) (*model.KYCDocument, error)
+	GetKYCDocuments(restaurantID string) ([]*model.KYCDocument, error)
+	ReviewKYCDocument(docID, status, reviewerID, comment string, now time.Time) error
+	GetExpiringKYCDocuments(before time.Time) ([]*model.KYCDocument, error)
+	MarkKYCExpiryNotified(docID string, now time.Time) error
+	GetExpiredKYCDocuments(now time.Time) ([]*model.KYCDocument, error)
+	HasValidKYCDocument(restaurantID, docType, excludeID string, after time.Time) (bool, error)
+	ExpireKYCDocument(docID string, ban *model.BanRecord) error
+
+	CreateReview(review *model.Review) error
+	GetReviewByID(reviewID string) (*model.Review, error)
+	GetReviews(restaurantID, productID string, includeHidden bool) ([]*model.Review, error)
+	ReplyToReview(reviewID, reply string, now time.Time) error
+	ModerateReview(reviewID, status, moderatorID, note string) error
+
+	GetTags(kind string) ([]*model.Tag, error)
+	GetTagsByIDs(tagIDs []string) ([]*model.Tag, error)
+	SetRestaurantCuisines(restaurantID string, tagIDs []string) error
+	SetProductTags(productID string, tagIDs []string) error
+	GetRestaurantsByCuisines(tagIDs []string) ([]*model.Restaurant, error)
+	GetProductsByTags(filter model.ProductTagFilter) ([]*model.Product, error)
+
+	Search(query model.SearchQuery) ([]*model.SearchHit, error)
+
+	ListProducts(opts model.ProductListOptions) (*model.ProductPage, error)
+	ListRestaurants(opts model.RestaurantListOptions) (*model.RestaurantPage, error)
+	GetProductsByRestaurantIDs(restaurantIDs []string) (map[string][]*model.Product, error)
+
+	GetRestaurantCurrencies(restaurantIDs []string) (map[string]string, error)
+	SetRestaurantCurrency(restaurantID, currency string) error
+
+	CreateTaxClass(taxClass *model.TaxClass) error
+	GetTaxClassByID(taxClassID string) (*model.TaxClass, error)
+	GetTaxClasses(restaurantID string) ([]*model.TaxClass, error)
+	SetProductTax(productID, taxClassID string, inclusive bool) error
+	GetProductsByIDs(productIDs []string) ([]*model.Product, error)
 
-	AddProduct(product *model.Product) error
+	CreatePromotion(promotion *model.Promotion) error
+	GetPromotionByID(promotionID string) (*model.Promotion, error)
+	GetPromotions(restaurantID string, activeOnly bool) ([]*model.Promotion, error)
+	SetPromotionActive(promotionID string, active bool) error
+	CountPromotionUsage(promotionIDs []string, customerID string) (map[string]int64, error)
+	RecordPromotionUsage(usages []*model.PromotionUsage) error
+
+	CreateCoupon(coupon *model.Coupon) error
+	GetCouponByCode(restaurantID, code string) (*model.Coupon, error)
+	CountCouponRedemptions(couponID, customerID string) (int64, error)
+	RedeemCoupon(coupon *model.Coupon, redemption *model.CouponRedemption, orderTotal model.Amount, now time.Time) error
+
+	CreatePriceSchedule(schedule *model.PriceSchedule) error
+	GetPriceScheduleByID(scheduleID uint) (*model.PriceSchedule, error)
+	GetPriceSchedules(productID string) ([]*model.PriceSchedule, error)
+	DeletePriceSchedule(scheduleID uint) error
+	GetCurrentPriceSchedules(productIDs []string, now time.Time) (map[string][]*model.PriceSchedule, error)
+	GetRestaurantTimezones(restaurantIDs []string) (map[string]string, error)
+
+	UpdateProductWithHistory(product *model.Product, actorID, note string) error
+	GetProductHistory(productID string) ([]*model.ProductVersion, error)
+	GetProductVersion(productID string, version int) (*model.ProductVersion, error)
+
+	GetProductIncludingDeleted(productID string) (*model.Product, error)
+	RestoreProduct(productID string) error
+	PurgeDeletedProducts(deletedBefore time.Time) (int64, error)
+
+	ImportProducts(restaurantID string, items []*model.ImportedProduct, actorID string, dryRun bool) (*model.ImportResult, error)
+
+	AddProduct(product *model.Product, currency string) error
 	GetProductByID(productID string) (*model.Product, error)
 	GetProductsByRestaurantID(restaurantID string) ([]*model.Product, error)
-	UpdateProduct(product *model.Product) error
 	DeleteProduct(productID string) error
 	GetAllProducts() ([]*model.Product, error)
+	GetVisibleProducts() ([]*model.Product, error)
 	UpdateProductStock(productID string, quantity int32) error
 	GetProductStock(productID string) (int32, error)
 	GetRestaurantWithProducts(restaurantID string) (*model.Restaurant, []*model.Product, error)
 	GetAllRestaurantsWithProducts() ([]*model.Restaurant, error)
+
+	SetOpeningHours(restaurantID string, hours []*model.OpeningHours) error
+	GetOpeningHours(restaurantID string) ([]*model.OpeningHours, error)
+	GetOpeningHoursByRestaurantIDs(restaurantIDs []string) (map[string][]*model.OpeningHours, error)
+	AddHolidayClosure(closure *model.HolidayClosure) error
+	DeleteHolidayClosure(restaurantID, date string) error
+	GetHolidayClosures(restaurantID string) ([]*model.HolidayClosure, error)
+	GetHolidayClosuresByRestaurantIDs(restaurantIDs []string) (map[string][]*model.HolidayClosure, error)
+	SetRestaurantTimezone(restaurantID, timezone string) error
+	SetRestaurantPaused(restaurantID string, paused bool) error
+
+	UpdateRestaurantLocation(restaurantID string, lat, lng float64) error
+	GetRestaurantsInBoundingBox(box model.BoundingBox) ([]*model.Restaurant, error)
+
+	AddDeliveryZone(zone *model.DeliveryZone) error
+	GetDeliveryZones(restaurantID string) ([]*model.DeliveryZone, error)
+	DeleteDeliveryZone(restaurantID, zoneID string) error
+
+	CreateBrand(brand *model.Brand) error
+	GetBrandByID(brandID string) (*model.Brand, error)
+	GetBrandByEmail(email string) (*model.Brand, error)
+	GetBranches(brandID string) ([]*model.Restaurant, error)
+	SetRestaurantBrand(restaurantID, brandID string) error
+	AddBrandMenuItem(item *model.BrandMenuItem) error
+	GetBrandMenu(brandID string) ([]*model.BrandMenuItem, error)
+	SyncBranchProducts(products []*model.Product, actorID string) error
+
+	TransitionRestaurantStatus(transition *model.RestaurantStatusTransition) error
+	GetRestaurantStatusHistory(restaurantID string) ([]*model.RestaurantStatusTransition, error)
 }
 
 type restaurantRepository struct {
 	db *gorm.DB
 }
 
+func (r *restaurantRepository) WithContext(ctx context.Context) RestaurantRepository {
+	return &restaurantRepository{db: r.db.WithContext(ctx)}
+}
+
 func NewRestaurantRepository(db *gorm.DB) RestaurantRepository {
 	return &restaurantRepository{db: db}
 }
 
+// visibleRestaurants limits a query to restaurants that may appear in public
+// listings: approved and not banned. Columns are qualified so the scope also
+// works on queries that join products to restaurants.
+func visibleRestaurants(db *gorm.DB) *gorm.DB {
+	return db.Where("restaurants.status = ? AND restaurants.is_banned = ?", model.StatusApproved, false)
+}
+
 // Restaurant operations
 func (r *restaurantRepository) CreateRestaurant(restaurant *model.Restaurant) error {
 	result := r.db.Create(restaurant)
@@ -47,6 +172,9 @@ func (r *restaurantRepository) CreateRestaurant(restaurant *model.Restaurant) er
 }
 
 func (r *restaurantRepository) GetRestaurantByEmail(email string) (*model.Restaurant, error) {
+	if email == "" {
+		return nil, model.ErrRestaurantNotFound
+	}
 	var restaurant model.Restaurant
 	result := r.db.Where("owner_email = ?", email).First(&restaurant)
 	if result.Error != nil {
@@ -70,11 +198,31 @@ func (r *restaurantRepository) GetRestaurantByID(id string) (*model.Restaurant,
 	return &restaurant, nil
 }
 
-func (r *restaurantRepository) UpdateRestaurant(restaurant *model.Restaurant) error {
-	result := r.db.Save(restaurant)
+// UpdateRestaurantProfile writes only the profile columns, so counters kept up
+// to date elsewhere, such as rating aggregates, are not overwritten with stale values.
+// restaurant.Version must be the version the caller read: the write fails with
+// model.ErrStaleVersion if the row has changed since, and bumps it otherwise.
+func (r *restaurantRepository) UpdateRestaurantProfile(restaurant *model.Restaurant) error {
+	result := r.db.Model(&model.Restaurant{}).
+		Where("id = ? AND version = ?", restaurant.ID, restaurant.Version).
+		Updates(map[string]interface{}{
+			"name":         restaurant.Name,
+			"phone_number": restaurant.PhoneNumber,
+			"street_name":  restaurant.StreetName,
+			"locality":     restaurant.Locality,
+			"state":        restaurant.State,
+			"pincode":      restaurant.Pincode,
+			"latitude":     restaurant.Latitude,
+			"longitude":    restaurant.Longitude,
+			"version":      gorm.Expr("version + 1"),
+		})
 	if result.Error != nil {
 		return fmt.Errorf("failed to update restaurant: %v", result.Error)
 	}
+	if result.RowsAffected == 0 {
+		return model.ErrStaleVersion
+	}
+	restaurant.Version++
 	return nil
 }
 
@@ -87,45 +235,44 @@ func (r *restaurantRepository) GetAllRestaurants() ([]*model.Restaurant, error)
 	return restaurants, nil
 }
 
-func (r *restaurantRepository) BanRestaurant(restaurantID, reason string) error {
-	result := r.db.Model(&model.Restaurant{}).
-		Where("id = ?", restaurantID).
-		Updates(map[string]interface{}{
-			"is_banned":  true,
-			"ban_reason": reason,
-		})
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return model.ErrRestaurantNotFound
-	}
-	return nil
-}
-
-func (r *restaurantRepository) UnbanRestaurant(restaurantID string) error {
-	result := r.db.Model(&model.Restaurant{}).
-		Where("id = ?", restaurantID).
-		Updates(map[string]interface{}{
-			"is_banned":  false,
-			"ban_reason": "",
-		})
+// GetVisibleRestaurants returns the restaurants customers may see in public listings.
+func (r *restaurantRepository) GetVisibleRestaurants() ([]*model.Restaurant, error) {
+	var restaurants []*model.Restaurant
+	result := r.db.Scopes(visibleRestaurants).Find(&restaurants)
 	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return model.ErrRestaurantNotFound
+		return nil, result.Error
 	}
-	return nil
+	return restaurants, nil
 }
 
 // Product operations
-func (r *restaurantRepository) AddProduct(product *model.Product) error {
-	result := r.db.Create(product)
-	if result.Error != nil {
-		return fmt.Errorf("failed to add product: %v", result.Error)
-	}
-	return nil
+
+// AddProduct stores a product priced in currency. The restaurant row is read
+// under a shared lock, which SetRestaurantCurrency's exclusive lock waits for,
+// and a restaurant whose currency changed since the price was converted fails
+// with model.ErrStaleVersion.
+func (r *restaurantRepository) AddProduct(product *model.Product, currency string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var restaurant model.Restaurant
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Select("id", "currency").
+			Where("id = ?", product.RestaurantID).
+			First(&restaurant).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrRestaurantNotFound
+			}
+			return err
+		}
+		if restaurant.CurrencyCode() != currency {
+			return model.ErrStaleVersion
+		}
+
+		if err := tx.Create(product).Error; err != nil {
+			return fmt.Errorf("failed to add product: %v", err)
+		}
+		return nil
+	})
 }
 
 func (r *restaurantRepository) GetProductByID(productID string) (*model.Product, error) {
@@ -149,14 +296,8 @@ func (r *restaurantRepository) GetProductsByRestaurantID(restaurantID string) ([
 	return products, nil
 }
 
-func (r *restaurantRepository) UpdateProduct(product *model.Product) error {
-	result := r.db.Save(product)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update product: %v", result.Error)
-	}
-	return nil
-}
-
+// DeleteProduct soft-deletes the product: Product.DeletedAt makes gorm set the
+// timestamp instead of removing the row, and hides the row from later queries.
 func (r *restaurantRepository) DeleteProduct(productID string) error {
 	result := r.db.Delete(&model.Product{}, "id = ?", productID)
 	if result.Error != nil {
@@ -177,10 +318,23 @@ func (r *restaurantRepository) GetAllProducts() ([]*model.Product, error) {
 	return products, nil
 }
 
+// GetVisibleProducts returns products of restaurants that appear in public listings.
+func (r *restaurantRepository) GetVisibleProducts() ([]*model.Product, error) {
+	var products []*model.Product
+	result := r.db.Joins("JOIN restaurants ON restaurants.id = products.restaurant_id").
+		Scopes(visibleRestaurants).
+		Find(&products)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return products, nil
+}
+
 func (r *restaurantRepository) UpdateProductStock(productID string, quantity int32) error {
 	result := r.db.Model(&model.Product{}).
 		Where("id = ?", productID).
-		Update("stock", gorm.Expr("stock + ?", quantity))
+		// UpdateColumn skips the audit hooks: orders moving stock are not edits
+		UpdateColumn("stock", gorm.Expr("stock + ?", quantity))
 	if result.Error != nil {
 		return result.Error
 	}
@@ -221,9 +375,13 @@ func (r *restaurantRepository) GetRestaurantWithProducts(restaurantID string) (*
 	return &restaurant, products, nil
 }
 
+// GetAllRestaurantsWithProducts returns the visible restaurants with their products
+// preloaded into Restaurant.Products: two queries no matter how many restaurants.
 func (r *restaurantRepository) GetAllRestaurantsWithProducts() ([]*model.Restaurant, error) {
 	var restaurants []*model.Restaurant
-	result := r.db.Find(&restaurants)
+	result := r.db.Scopes(visibleRestaurants).
+		Preload("Products").
+		Find(&restaurants)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// catalogDriver is a database/sql driver serving a synthetic catalog of
+// restaurants, each with productsPer products. It counts the queries it answers,
+// which is all the benchmarks below need from a database.
+type catalogDriver struct {
+	restaurants int
+	productsPer int
+	queries     atomic.Int64
+}
+
+func (d *catalogDriver) Open(string) (driver.Conn, error) { return &catalogConn{d: d}, nil }
+
+type catalogConn struct{ d *catalogDriver }
+
+func (c *catalogConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported: %s", query)
+}
+func (c *catalogConn) Close() error { return nil }
+func (c *catalogConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions not supported")
+}
+
+func (c *catalogConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.queries.Add(1)
+	switch {
+	case strings.Contains(query, "FROM `restaurants`"):
+		rows := &catalogRows{columns: []string{"id", "name"}}
+		for i := 0; i < c.d.restaurants; i++ {
+			rows.values = append(rows.values, []driver.Value{fmt.Sprintf("rest_%d", i), fmt.Sprintf("Restaurant %d", i)})
+		}
+		return rows, nil
+	case strings.Contains(query, "FROM `products`"):
+		// One page of products for every restaurant ID among the arguments, so the
+		// same driver answers both the per-restaurant and the IN (...) form.
+		rows := &catalogRows{columns: []string{"id", "restaurant_id", "name", "price_minor"}}
+		for _, arg := range args {
+			id, ok := arg.Value.(string)
+			if !ok || !strings.HasPrefix(id, "rest_") {
+				continue
+			}
+			for j := 0; j < c.d.productsPer; j++ {
+				rows.values = append(rows.values, []driver.Value{fmt.Sprintf("prod_%s_%d", id, j), id, "Dish", int64(25000)})
+			}
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+type catalogRows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *catalogRows) Columns() []string { return r.columns }
+func (r *catalogRows) Close() error      { return nil }
+
+func (r *catalogRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+func newCatalogRepository(b *testing.B, restaurants, productsPer int) (*restaurantRepository, *catalogDriver) {
+	b.Helper()
+	d := &catalogDriver{restaurants: restaurants, productsPer: productsPer}
+	conn, err := d.Open("")
+	if err != nil {
+		b.Fatal(err)
+	}
+	sqlDB := sql.OpenDB(singleConnector{conn: conn, d: d})
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+		Logger:                 logger.Discard,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return &restaurantRepository{db: db}, d
+}
+
+type singleConnector struct {
+	conn driver.Conn
+	d    *catalogDriver
+}
+
+func (c singleConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c singleConnector) Driver() driver.Driver                        { return c.d }
+
+// loadPerRestaurant is how GetAllRestaurantWithProducts used to load the catalog:
+// one product query per restaurant.
+func loadPerRestaurant(r *restaurantRepository) (int, error) {
+	restaurants, err := r.GetVisibleRestaurants()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, rest := range restaurants {
+		products, err := r.GetProductsByRestaurantID(rest.ID)
+		if err != nil {
+			return 0, err
+		}
+		total += len(products)
+	}
+	return total, nil
+}
+
+func loadPreloaded(r *restaurantRepository) (int, error) {
+	restaurants, err := r.GetAllRestaurantsWithProducts()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, rest := range restaurants {
+		total += len(rest.Products)
+	}
+	return total, nil
+}
+
+// BenchmarkGetAllRestaurantsWithProducts compares the per-restaurant loading with
+// the preloading query on catalogs of a few thousand products. The queries/op
+// metric stays at 2 for the preload while it grows with the restaurant count
+// for the per-restaurant version. The in-memory driver has no network round
+// trips, so ns/op understates what each extra query costs against MySQL.
+func BenchmarkGetAllRestaurantsWithProducts(b *testing.B) {
+	loaders := []struct {
+		name string
+		load func(*restaurantRepository) (int, error)
+	}{
+		{"PerRestaurant", loadPerRestaurant},
+		{"Preload", loadPreloaded},
+	}
+	for _, size := range []struct{ restaurants, productsPer int }{{50, 40}, {200, 20}} {
+		for _, l := range loaders {
+			b.Run(fmt.Sprintf("%s/restaurants=%d", l.name, size.restaurants), func(b *testing.B) {
+				repo, d := newCatalogRepository(b, size.restaurants, size.productsPer)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					n, err := l.load(repo)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if n != size.restaurants*size.productsPer {
+						b.Fatalf("loaded %d products, want %d", n, size.restaurants*size.productsPer)
+					}
+				}
+				b.ReportMetric(float64(d.queries.Load())/float64(b.N), "queries/op")
+			})
+		}
+	}
+}
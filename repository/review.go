@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Review operations
+
+// CreateReview stores a review and adds its rating to the restaurant aggregate,
+// and to the product aggregate for product reviews, in one transaction.
+func (r *restaurantRepository) CreateReview(review *model.Review) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		err := tx.Model(&model.Review{}).
+			Where("order_id = ? AND product_id = ?", review.OrderID, review.ProductID).
+			Count(&existing).Error
+		if err != nil {
+			return err
+		}
+		if existing > 0 {
+			return model.ErrDuplicateReview
+		}
+
+		// The count above does not stop two concurrent submissions; the unique
+		// index on (order_id, product_id) does
+		if err := tx.Create(review).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return model.ErrDuplicateReview
+			}
+			return fmt.Errorf("failed to create review: %v", err)
+		}
+		return applyRating(tx, review, 1)
+	})
+}
+
+func (r *restaurantRepository) GetReviewByID(reviewID string) (*model.Review, error) {
+	var review model.Review
+	result := r.db.Where("id = ?", reviewID).First(&review)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrReviewNotFound
+		}
+		return nil, result.Error
+	}
+	return &review, nil
+}
+
+// GetReviews lists reviews of a restaurant, newest first. A non-empty productID
+// narrows the list to that product.
+func (r *restaurantRepository) GetReviews(restaurantID, productID string, includeHidden bool) ([]*model.Review, error) {
+	var reviews []*model.Review
+	query := r.db.Where("restaurant_id = ?", restaurantID)
+	if productID != "" {
+		query = query.Where("product_id = ?", productID)
+	}
+	if !includeHidden {
+		query = query.Where("status = ?", model.ReviewVisible)
+	}
+	result := query.Order("created_at DESC").Find(&reviews)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return reviews, nil
+}
+
+func (r *restaurantRepository) ReplyToReview(reviewID, reply string, now time.Time) error {
+	result := r.db.Model(&model.Review{}).
+		Where("id = ?", reviewID).
+		Updates(map[string]interface{}{
+			"owner_reply":      reply,
+			"owner_replied_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrReviewNotFound
+	}
+	return nil
+}
+
+// ModerateReview changes a review's visibility. Hidden reviews do not count
+// towards the aggregates, so the rating is subtracted or re-added accordingly.
+func (r *restaurantRepository) ModerateReview(reviewID, status, moderatorID, note string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var review model.Review
+		if err := tx.Where("id = ?", reviewID).First(&review).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.ErrReviewNotFound
+			}
+			return err
+		}
+
+		result := tx.Model(&model.Review{}).
+			Where("id = ? AND status = ?", reviewID, review.Status).
+			Updates(map[string]interface{}{
+				"status":          status,
+				"moderated_by":    moderatorID,
+				"moderation_note": note,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 || review.Status == status {
+			return nil
+		}
+
+		sign := int64(1)
+		if status == model.ReviewHidden {
+			sign = -1
+		}
+		return applyRating(tx, &review, sign)
+	})
+}
+
+// applyRating adds (sign 1) or removes (sign -1) a review's rating from the
+// aggregates. UpdateColumns skips the audit hooks: a review is not an edit of the
+// restaurant or product.
+func applyRating(tx *gorm.DB, review *model.Review, sign int64) error {
+	updates := map[string]interface{}{
+		"rating_total": gorm.Expr("rating_total + ?", sign*int64(review.Rating)),
+		"rating_count": gorm.Expr("rating_count + ?", sign),
+	}
+
+	err := tx.Model(&model.Restaurant{}).Where("id = ?", review.RestaurantID).UpdateColumns(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to update restaurant rating: %v", err)
+	}
+
+	if review.ProductID == "" {
+		return nil
+	}
+	err = tx.Model(&model.Product{}).Where("id = ?", review.ProductID).UpdateColumns(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to update product rating: %v", err)
+	}
+	return nil
+}
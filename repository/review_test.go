@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// recordingDriver is a database/sql driver that accepts every statement and
+// records it with its arguments, for tests that check the SQL a write issues.
+type recordingDriver struct {
+	execs []recordedExec
+}
+
+type recordedExec struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return recordingConn{d: d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported: %s", query)
+}
+func (c recordingConn) Close() error { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions not supported")
+}
+
+func (c recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	exec := recordedExec{query: query}
+	for _, a := range args {
+		exec.args = append(exec.args, a.Value)
+	}
+	c.d.execs = append(c.d.execs, exec)
+	return driver.RowsAffected(1), nil
+}
+
+type recordingConnector struct{ d *recordingDriver }
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return recordingConn{d: c.d}, nil
+}
+func (c recordingConnector) Driver() driver.Driver { return c.d }
+
+func newRecordingDB(t *testing.T) (*gorm.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{}
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(recordingConnector{d: d}), SkipInitializeWithVersion: true}), &gorm.Config{
+		Logger:                 logger.Discard,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, d
+}
+
+func TestApplyRating(t *testing.T) {
+	tests := []struct {
+		name   string
+		review model.Review
+		sign   int64
+		tables []string
+		args   []driver.Value // rating_count delta, rating_total delta
+	}{
+		{
+			name:   "restaurant review added",
+			review: model.Review{RestaurantID: "rest_1", Rating: 4},
+			sign:   1,
+			tables: []string{"restaurants"},
+			args:   []driver.Value{int64(1), int64(4)},
+		},
+		{
+			name:   "product review added",
+			review: model.Review{RestaurantID: "rest_1", ProductID: "prod_1", Rating: 5},
+			sign:   1,
+			tables: []string{"restaurants", "products"},
+			args:   []driver.Value{int64(1), int64(5)},
+		},
+		{
+			name:   "product review hidden",
+			review: model.Review{RestaurantID: "rest_1", ProductID: "prod_1", Rating: 2},
+			sign:   -1,
+			tables: []string{"restaurants", "products"},
+			args:   []driver.Value{int64(-1), int64(-2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, d := newRecordingDB(t)
+			if err := applyRating(db, &tt.review, tt.sign); err != nil {
+				t.Fatalf("applyRating() error = %v", err)
+			}
+			if len(d.execs) != len(tt.tables) {
+				t.Fatalf("%d statements, want %d: %v", len(d.execs), len(tt.tables), d.execs)
+			}
+			for i, exec := range d.execs {
+				if !strings.HasPrefix(exec.query, "UPDATE `"+tt.tables[i]+"`") {
+					t.Errorf("statement %d = %q, want an update of %s", i, exec.query, tt.tables[i])
+				}
+				// Aggregates move by the review alone, relative to the stored values,
+				// and a review is not an edit: no audit columns or version bump.
+				for _, want := range []string{"`rating_count`=rating_count + ?", "`rating_total`=rating_total + ?"} {
+					if !strings.Contains(exec.query, want) {
+						t.Errorf("statement %d = %q, missing %s", i, exec.query, want)
+					}
+				}
+				for _, unwanted := range []string{"updated_at", "updated_by", "version"} {
+					if strings.Contains(exec.query, unwanted) {
+						t.Errorf("statement %d = %q, touches %s", i, exec.query, unwanted)
+					}
+				}
+				if got := exec.args[:2]; !reflect.DeepEqual(got, tt.args) {
+					t.Errorf("statement %d deltas = %v, want %v", i, got, tt.args)
+				}
+			}
+		})
+	}
+}
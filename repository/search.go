@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"sort"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Search operations
+
+const (
+	productMatch    = "MATCH(products.name, products.description, products.category) AGAINST (? IN BOOLEAN MODE)"
+	restaurantMatch = "MATCH(restaurants.name, restaurants.locality) AGAINST (? IN BOOLEAN MODE)"
+)
+
+type restaurantHit struct {
+	model.Restaurant
+	Score float64 `gorm:"column:score"`
+}
+
+type productHit struct {
+	model.Product
+	Score float64 `gorm:"column:score"`
+}
+
+// Search ranks visible restaurants and products against the query text. MySQL
+// uses the FULLTEXT indexes created at startup; other drivers fall back to LIKE
+// matching with a simple term-based score.
+func (r *restaurantRepository) Search(query model.SearchQuery) ([]*model.SearchHit, error) {
+	terms := model.SearchTerms(query.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if query.Limit <= 0 {
+		// gorm treats a negative limit as "no limit"
+		query.Limit = -1
+	}
+
+	var hits []*model.SearchHit
+	var err error
+	if r.db.Dialector.Name() == "mysql" {
+		hits, err = r.fullTextSearch(terms, query)
+	} else {
+		hits, err = r.likeSearch(terms, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits, nil
+}
+
+func (r *restaurantRepository) fullTextSearch(terms []string, query model.SearchQuery) ([]*model.SearchHit, error) {
+	expr := model.BooleanModeQuery(terms)
+
+	var restaurants []restaurantHit
+	err := r.db.Model(&model.Restaurant{}).
+		Select("restaurants.*, "+restaurantMatch+" AS score", expr).
+		Scopes(visibleRestaurants, pincodeFilter(query.Pincode)).
+		Where(restaurantMatch, expr).
+		Order("score DESC").
+		Limit(query.Limit).
+		Find(&restaurants).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Products match across their own and their restaurant's fields, which are
+	// two indexes: "paneer koramangala" has paneer in the dish and koramangala in
+	// the restaurant. So each term must match one of the two, and the ranking
+	// scores any term against both.
+	rank := model.BooleanModeAny(terms)
+	productQuery := r.db.Model(&model.Product{}).
+		Select("products.*, "+productMatch+" + "+restaurantMatch+" AS score", rank, rank).
+		Joins("JOIN restaurants ON restaurants.id = products.restaurant_id").
+		Scopes(visibleRestaurants, pincodeFilter(query.Pincode))
+	for _, term := range model.BooleanModeTerms(terms) {
+		productQuery = productQuery.Where("("+productMatch+" OR "+restaurantMatch+")", term, term)
+	}
+
+	var products []productHit
+	err = productQuery.
+		Order("score DESC").
+		Limit(query.Limit).
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.buildHits(restaurants, products)
+}
+
+func (r *restaurantRepository) likeSearch(terms []string, query model.SearchQuery) ([]*model.SearchHit, error) {
+	restaurantQuery := r.db.Model(&model.Restaurant{}).
+		Scopes(visibleRestaurants, pincodeFilter(query.Pincode))
+	productQuery := r.db.Model(&model.Product{}).
+		Select("products.*").
+		Joins("JOIN restaurants ON restaurants.id = products.restaurant_id").
+		Scopes(visibleRestaurants, pincodeFilter(query.Pincode))
+
+	for _, t := range terms {
+		pattern := "%" + t + "%"
+		restaurantQuery = restaurantQuery.
+			Where("(LOWER(restaurants.name) LIKE ? OR LOWER(restaurants.locality) LIKE ?)", pattern, pattern)
+		productQuery = productQuery.
+			Where("(LOWER(products.name) LIKE ? OR LOWER(products.description) LIKE ? OR LOWER(products.category) LIKE ?"+
+				" OR LOWER(restaurants.name) LIKE ? OR LOWER(restaurants.locality) LIKE ?)",
+				pattern, pattern, pattern, pattern, pattern)
+	}
+
+	var restaurants []restaurantHit
+	if err := restaurantQuery.Find(&restaurants).Error; err != nil {
+		return nil, err
+	}
+	for i := range restaurants {
+		restaurants[i].Score = model.TextScore(terms, restaurants[i].Name, restaurants[i].Locality)
+	}
+
+	var products []productHit
+	if err := productQuery.Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	hits, err := r.buildHits(restaurants, products)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hits {
+		if h.Kind == model.SearchHitProduct {
+			h.Score = model.TextScore(terms, h.Product.Name, h.Product.Description, h.Product.Category) +
+				model.TextScore(terms, h.Restaurant.Name, h.Restaurant.Locality)
+		}
+	}
+	return hits, nil
+}
+
+// buildHits attaches each product's restaurant, loading the ones that did not
+// match on their own in a single query.
+func (r *restaurantRepository) buildHits(restaurants []restaurantHit, products []productHit) ([]*model.SearchHit, error) {
+	byID := make(map[string]*model.Restaurant, len(restaurants))
+	hits := make([]*model.SearchHit, 0, len(restaurants)+len(products))
+	for i := range restaurants {
+		rest := &restaurants[i].Restaurant
+		byID[rest.ID] = rest
+		hits = append(hits, &model.SearchHit{
+			Kind:       model.SearchHitRestaurant,
+			Restaurant: rest,
+			Score:      restaurants[i].Score,
+		})
+	}
+
+	var missing []string
+	for _, p := range products {
+		if _, ok := byID[p.RestaurantID]; !ok {
+			missing = append(missing, p.RestaurantID)
+		}
+	}
+	if len(missing) > 0 {
+		var extra []*model.Restaurant
+		if err := r.db.Where("id IN ?", missing).Find(&extra).Error; err != nil {
+			return nil, err
+		}
+		for _, rest := range extra {
+			byID[rest.ID] = rest
+		}
+	}
+
+	for i := range products {
+		hits = append(hits, &model.SearchHit{
+			Kind:       model.SearchHitProduct,
+			Restaurant: byID[products[i].RestaurantID],
+			Product:    &products[i].Product,
+			Score:      products[i].Score,
+		})
+	}
+	return hits, nil
+}
+
+// pincodeFilter narrows a restaurant query to one pincode when it is set.
+func pincodeFilter(pincode string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if pincode == "" {
+			return db
+		}
+		return db.Where("restaurants.pincode = ?", pincode)
+	}
+}
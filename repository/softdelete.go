@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Deleted product operations
+
+// GetProductIncludingDeleted loads a product whether or not it has been soft-deleted.
+func (r *restaurantRepository) GetProductIncludingDeleted(productID string) (*model.Product, error) {
+	var product model.Product
+	result := r.db.Unscoped().Where("id = ?", productID).First(&product)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrProductNotFound
+		}
+		return nil, result.Error
+	}
+	return &product, nil
+}
+
+func (r *restaurantRepository) RestoreProduct(productID string) error {
+	result := r.db.Unscoped().Model(&model.Product{}).
+		Where("id = ? AND deleted_at IS NOT NULL", productID).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to restore product: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrProductNotDeleted
+	}
+	return nil
+}
+
+// PurgeDeletedProducts permanently removes products soft-deleted before the
+// cutoff, along with their tags and price schedules, and returns how many were
+// removed. Version history and reviews are kept.
+func (r *restaurantRepository) PurgeDeletedProducts(deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		err := tx.Unscoped().Model(&model.Product{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Where("product_id IN ?", ids).Delete(&model.ProductTag{}).Error; err != nil {
+			return fmt.Errorf("failed to purge product tags: %v", err)
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&model.PriceSchedule{}).Error; err != nil {
+			return fmt.Errorf("failed to purge price schedules: %v", err)
+		}
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&model.Product{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge products: %v", result.Error)
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"fmt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Onboarding status operations
+
+// TransitionRestaurantStatus moves a restaurant from transition.FromStatus to
+// transition.ToStatus and stores the transition. The update is conditional on the
+// current status, so a concurrent change makes it fail instead of being overwritten.
+func (r *restaurantRepository) TransitionRestaurantStatus(transition *model.RestaurantStatusTransition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Restaurant{}).
+			Where("id = ? AND status = ?", transition.RestaurantID, transition.FromStatus).
+			Update("status", transition.ToStatus)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update restaurant status: %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrInvalidStatusChange
+		}
+
+		if err := tx.Create(transition).Error; err != nil {
+			return fmt.Errorf("failed to record status transition: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *restaurantRepository) GetRestaurantStatusHistory(restaurantID string) ([]*model.RestaurantStatusTransition, error) {
+	var history []*model.RestaurantStatusTransition
+	result := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("created_at, id").
+		Find(&history)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return history, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"fmt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Tag operations
+
+// GetTags returns the vocabulary for one tag kind, or every tag when kind is empty.
+func (r *restaurantRepository) GetTags(kind string) ([]*model.Tag, error) {
+	var tags []*model.Tag
+	query := r.db.Order("kind, label")
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	result := query.Find(&tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+func (r *restaurantRepository) GetTagsByIDs(tagIDs []string) ([]*model.Tag, error) {
+	var tags []*model.Tag
+	if len(tagIDs) == 0 {
+		return tags, nil
+	}
+	result := r.db.Where("id IN ?", tagIDs).Find(&tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+func (r *restaurantRepository) SetRestaurantCuisines(restaurantID string, tagIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("restaurant_id = ?", restaurantID).Delete(&model.RestaurantCuisine{}).Error; err != nil {
+			return fmt.Errorf("failed to clear cuisines: %v", err)
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		links := make([]model.RestaurantCuisine, 0, len(tagIDs))
+		for _, id := range tagIDs {
+			links = append(links, model.RestaurantCuisine{RestaurantID: restaurantID, TagID: id})
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return fmt.Errorf("failed to save cuisines: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *restaurantRepository) SetProductTags(productID string, tagIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&model.ProductTag{}).Error; err != nil {
+			return fmt.Errorf("failed to clear product tags: %v", err)
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		links := make([]model.ProductTag, 0, len(tagIDs))
+		for _, id := range tagIDs {
+			links = append(links, model.ProductTag{ProductID: productID, TagID: id})
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return fmt.Errorf("failed to save product tags: %v", err)
+		}
+		return nil
+	})
+}
+
+// GetRestaurantsByCuisines returns visible restaurants serving any of the cuisines.
+func (r *restaurantRepository) GetRestaurantsByCuisines(tagIDs []string) ([]*model.Restaurant, error) {
+	var restaurants []*model.Restaurant
+	result := r.db.Scopes(visibleRestaurants).
+		Where("restaurants.id IN (?)", r.db.Model(&model.RestaurantCuisine{}).
+			Select("restaurant_id").
+			Where("tag_id IN ?", tagIDs)).
+		Find(&restaurants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return restaurants, nil
+}
+
+// GetProductsByTags returns products of visible restaurants that carry every
+// requested diet tag and none of the excluded allergens.
+func (r *restaurantRepository) GetProductsByTags(filter model.ProductTagFilter) ([]*model.Product, error) {
+	var products []*model.Product
+	query := r.db.Joins("JOIN restaurants ON restaurants.id = products.restaurant_id").
+		Scopes(visibleRestaurants)
+
+	if len(filter.Diets) > 0 {
+		query = query.Where("products.id IN (?)", r.db.Model(&model.ProductTag{}).
+			Select("product_id").
+			Where("tag_id IN ?", filter.Diets).
+			Group("product_id").
+			Having("COUNT(DISTINCT tag_id) = ?", len(filter.Diets)))
+	}
+	if len(filter.ExcludeAllergens) > 0 {
+		query = query.Where("products.id NOT IN (?)", r.db.Model(&model.ProductTag{}).
+			Select("product_id").
+			Where("tag_id IN ?", filter.ExcludeAllergens))
+	}
+	if filter.Category != "" {
+		query = query.Where("products.category = ?", filter.Category)
+	}
+
+	result := query.Find(&products)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return products, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"gorm.io/gorm"
+)
+
+// Tax class operations
+func (r *restaurantRepository) CreateTaxClass(taxClass *model.TaxClass) error {
+	result := r.db.Create(taxClass)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create tax class: %v", result.Error)
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetTaxClassByID(taxClassID string) (*model.TaxClass, error) {
+	var taxClass model.TaxClass
+	result := r.db.Where("id = ?", taxClassID).First(&taxClass)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, model.ErrTaxClassNotFound
+		}
+		return nil, result.Error
+	}
+	return &taxClass, nil
+}
+
+func (r *restaurantRepository) GetTaxClasses(restaurantID string) ([]*model.TaxClass, error) {
+	var taxClasses []*model.TaxClass
+	result := r.db.Where("restaurant_id = ?", restaurantID).Find(&taxClasses)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return taxClasses, nil
+}
+
+func (r *restaurantRepository) SetProductTax(productID, taxClassID string, inclusive bool) error {
+	result := r.db.Model(&model.Product{}).
+		Where("id = ?", productID).
+		Updates(map[string]interface{}{
+			"tax_class_id":  taxClassID,
+			"tax_inclusive": inclusive,
+			"version":       gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *restaurantRepository) GetProductsByIDs(productIDs []string) ([]*model.Product, error) {
+	var products []*model.Product
+	if len(productIDs) == 0 {
+		return products, nil
+	}
+	result := r.db.Where("id IN ?", productIDs).Find(&products)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return products, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// appealTokenTTL is how long a banned owner may use an appeal token.
+const appealTokenTTL = 24 * time.Hour
+
+// appealTokenPurpose is signed into every token so it cannot be confused with other HMAC tokens.
+const appealTokenPurpose = "ban-appeal"
+
+// RequestAppealToken checks a banned restaurant's credentials and returns a token
+// that only grants access to SubmitBanAppeal. RestaurantLogin keeps refusing
+// banned accounts, so this is their one way back into the system.
+func (s *RestaurantService) RequestAppealToken(ctx context.Context, ownerEmail, password string) (string, error) {
+	restaurant, err := s.repo.GetRestaurantByEmail(ownerEmail)
+	if err != nil {
+		return "", model.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(restaurant.PasswordHash), []byte(password)); err != nil {
+		return "", model.ErrInvalidCredentials
+	}
+
+	if !restaurant.IsBanned {
+		return "", model.ErrRestaurantNotBanned
+	}
+
+	return s.signAppealToken(restaurant.ID, time.Now().Add(appealTokenTTL)), nil
+}
+
+// SubmitBanAppeal files an appeal against one of the restaurant's active bans.
+func (s *RestaurantService) SubmitBanAppeal(ctx context.Context, appealToken, banID, message string) (string, error) {
+	restaurantID, err := s.verifyAppealToken(appealToken, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	ban, err := s.repo.GetBanByID(banID)
+	if err != nil {
+		return "", err
+	}
+	if ban.RestaurantID != restaurantID {
+		return "", model.ErrBanNotFound
+	}
+	if !ban.IsActive(time.Now()) {
+		return "", model.ErrBanNotActive
+	}
+
+	appeal := &model.BanAppeal{
+		ID:           fmt.Sprintf("appeal_%s", uuid.New().String()),
+		RestaurantID: restaurantID,
+		BanID:        banID,
+		Message:      message,
+		Status:       model.AppealPending,
+	}
+	if err := s.repo.CreateBanAppeal(appeal); err != nil {
+		return "", err
+	}
+	return appeal.ID, nil
+}
+
+// ListBanAppeals returns appeals for admin review, optionally filtered by status.
+func (s *RestaurantService) ListBanAppeals(ctx context.Context, status string) ([]*model.BanAppeal, error) {
+	return s.repo.ListBanAppeals(status)
+}
+
+// GetBanAppeal returns a single appeal.
+func (s *RestaurantService) GetBanAppeal(ctx context.Context, appealID string) (*model.BanAppeal, error) {
+	return s.repo.GetBanAppealByID(appealID)
+}
+
+// ReviewBanAppeal accepts or rejects a pending appeal. Accepting lifts the linked
+// ban through the regular ban logic, in the same transaction as the decision,
+// which unbans the restaurant if nothing else is holding it. The authenticated
+// caller is recorded as the reviewer.
+func (s *RestaurantService) ReviewBanAppeal(ctx context.Context, appealID string, accept bool, comment string) error {
+	reviewerID, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+
+	appeal, err := s.repo.GetBanAppealByID(appealID)
+	if err != nil {
+		return err
+	}
+
+	status := model.AppealRejected
+	if accept {
+		status = model.AppealAccepted
+	}
+
+	note := fmt.Sprintf("appeal %s accepted", appeal.ID)
+	if comment != "" {
+		note = fmt.Sprintf("%s: %s", note, comment)
+	}
+	return s.repo.WithContext(ctx).ReviewBanAppeal(appeal.ID, status, reviewerID, comment, note, time.Now())
+}
+
+func (s *RestaurantService) signAppealToken(restaurantID string, expiresAt time.Time) string {
+	payload := strings.Join([]string{appealTokenPurpose, restaurantID, strconv.FormatInt(expiresAt.Unix(), 10)}, "|")
+	mac := hmac.New(sha256.New, s.appealSecret)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *RestaurantService) verifyAppealToken(token string, now time.Time) (string, error) {
+	if len(s.appealSecret) == 0 {
+		return "", model.ErrInvalidAppealToken
+	}
+
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", model.ErrInvalidAppealToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", model.ErrInvalidAppealToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return "", model.ErrInvalidAppealToken
+	}
+
+	mac := hmac.New(sha256.New, s.appealSecret)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return "", model.ErrInvalidAppealToken
+	}
+
+	parts := strings.Split(string(payload), "|")
+	if len(parts) != 3 || parts[0] != appealTokenPurpose {
+		return "", model.ErrInvalidAppealToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || now.Unix() >= expiresAt {
+		return "", model.ErrInvalidAppealToken
+	}
+	return parts[1], nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// signPayload signs an arbitrary payload the way signAppealToken does, to build
+// tokens the service would never issue itself.
+func signPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAppealToken(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	issuer := NewRestaurantService(nil, "appeal-secret", LogNotifier{})
+	valid := issuer.signAppealToken("rest_1", now.Add(appealTokenTTL))
+
+	payload, sig, _ := strings.Cut(valid, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte("ban-appeal|rest_2|9999999999"))
+
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		now     time.Time
+		want    string
+		wantErr error
+	}{
+		{name: "valid", secret: "appeal-secret", token: valid, now: now, want: "rest_1"},
+		{name: "just before expiry", secret: "appeal-secret", token: valid, now: now.Add(appealTokenTTL - time.Second), want: "rest_1"},
+		{name: "expired", secret: "appeal-secret", token: valid, now: now.Add(appealTokenTTL), wantErr: model.ErrInvalidAppealToken},
+		{name: "other secret", secret: "jwt-secret", token: valid, now: now, wantErr: model.ErrInvalidAppealToken},
+		{name: "no secret configured", secret: "", token: valid, now: now, wantErr: model.ErrInvalidAppealToken},
+		{name: "payload swapped", secret: "appeal-secret", token: forged + "." + sig, now: now, wantErr: model.ErrInvalidAppealToken},
+		{name: "signature dropped", secret: "appeal-secret", token: payload + ".", now: now, wantErr: model.ErrInvalidAppealToken},
+		{name: "not a token", secret: "appeal-secret", token: "garbage", now: now, wantErr: model.ErrInvalidAppealToken},
+		{
+			name:    "signed for another purpose",
+			secret:  "appeal-secret",
+			token:   signPayload("appeal-secret", "password-reset|rest_1|9999999999"),
+			now:     now,
+			wantErr: model.ErrInvalidAppealToken,
+		},
+		{
+			name:    "malformed expiry",
+			secret:  "appeal-secret",
+			token:   signPayload("appeal-secret", "ban-appeal|rest_1|tomorrow"),
+			now:     now,
+			wantErr: model.ErrInvalidAppealToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewRestaurantService(nil, tt.secret, LogNotifier{})
+			got, err := s.verifyAppealToken(tt.token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verifyAppealToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("verifyAppealToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewBanAppeal(t *testing.T) {
+	tests := []struct {
+		name       string
+		actor      string
+		accept     bool
+		wantErr    error
+		wantStatus string
+		wantLifted bool
+	}{
+		{name: "accepted", actor: "admin_7", accept: true, wantStatus: model.AppealAccepted, wantLifted: true},
+		{name: "rejected", actor: "admin_7", wantStatus: model.AppealRejected},
+		{name: "no authenticated caller", accept: true, wantErr: model.ErrActorRequired, wantStatus: model.AppealPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.bans["ban_1"] = &model.BanRecord{ID: "ban_1", RestaurantID: "rest_1"}
+			repo.appeals["appeal_1"] = &model.BanAppeal{ID: "appeal_1", RestaurantID: "rest_1", BanID: "ban_1", Status: model.AppealPending}
+			s := NewRestaurantService(repo, "appeal-secret", LogNotifier{})
+
+			ctx := context.Background()
+			if tt.actor != "" {
+				ctx = model.WithActor(ctx, tt.actor)
+			}
+			err := s.ReviewBanAppeal(ctx, "appeal_1", tt.accept, "cleaned up")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReviewBanAppeal() error = %v, want %v", err, tt.wantErr)
+			}
+
+			appeal := repo.appeals["appeal_1"]
+			if appeal.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", appeal.Status, tt.wantStatus)
+			}
+			if tt.wantErr == nil && appeal.ReviewerID != tt.actor {
+				t.Errorf("reviewer = %q, want the authenticated caller %q", appeal.ReviewerID, tt.actor)
+			}
+			ban := repo.bans["ban_1"]
+			if lifted := ban.LiftedAt != nil; lifted != tt.wantLifted {
+				t.Fatalf("ban lifted = %v, want %v", lifted, tt.wantLifted)
+			}
+			if tt.wantLifted && (ban.LiftedBy != tt.actor || ban.LiftNote != "appeal appeal_1 accepted: cleaned up") {
+				t.Errorf("ban lifted by %q with note %q", ban.LiftedBy, ban.LiftNote)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// SuspendRestaurant bans a restaurant and records who did it and why. A zero
+// duration bans indefinitely; otherwise the suspension lifts itself once it ends.
+func (s *RestaurantService) SuspendRestaurant(ctx context.Context, restaurantID, issuedBy, category, reason string, duration time.Duration) (string, error) {
+	if !model.ValidBanCategory(category) {
+		return "", model.ErrInvalidBanCategory
+	}
+	if duration < 0 {
+		return "", model.ErrInvalidBanDuration
+	}
+
+	ban := newBanRecord(restaurantID, issuedBy, category, reason, duration, time.Now())
+	if err := s.repo.WithContext(ctx).BanRestaurant(ban); err != nil {
+		return "", err
+	}
+	return ban.ID, nil
+}
+
+// newBanRecord builds a ban starting at now; a zero duration makes it indefinite.
+func newBanRecord(restaurantID, issuedBy, category, reason string, duration time.Duration, now time.Time) *model.BanRecord {
+	ban := &model.BanRecord{
+		ID:           fmt.Sprintf("ban_%s", uuid.New().String()),
+		RestaurantID: restaurantID,
+		IssuedBy:     issuedBy,
+		Category:     category,
+		Reason:       reason,
+		StartsAt:     now,
+	}
+	if duration > 0 {
+		endsAt := now.Add(duration)
+		ban.EndsAt = &endsAt
+	}
+	return ban
+}
+
+// GetBanHistory returns every ban ever issued against a restaurant, newest first.
+func (s *RestaurantService) GetBanHistory(ctx context.Context, restaurantID string) ([]*model.BanRecord, error) {
+	if _, err := s.repo.GetRestaurantByID(restaurantID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBanHistory(restaurantID)
+}
+
+// LiftExpiredSuspensions closes every timed ban whose end time has passed. A ban
+// that fails to lift is logged and retried on the next run, without holding up
+// the others.
+func (s *RestaurantService) LiftExpiredSuspensions(now time.Time) error {
+	bans, err := s.repo.GetExpiredBans(now)
+	if err != nil {
+		return fmt.Errorf("failed to get expired bans: %v", err)
+	}
+
+	repo := s.repo.WithContext(model.WithActor(context.Background(), model.ActorSystem))
+	failed := 0
+	for _, ban := range bans {
+		if err := repo.LiftBan(ban.ID, model.ActorSystem, "suspension expired", now); err != nil {
+			log.Printf("Failed to lift ban %s: %v", ban.ID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to lift %d of %d expired bans", failed, len(bans))
+	}
+	return nil
+}
+
+// StartSuspensionExpiryJob lifts expired suspensions every interval until ctx is done.
+func (s *RestaurantService) StartSuspensionExpiryJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := s.LiftExpiredSuspensions(now); err != nil {
+					log.Printf("Suspension expiry job failed: %v", err)
+				}
+			}
+		}
+	}()
+}
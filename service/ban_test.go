@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	restaurantPb "github.com/liju-github/CentralisedFoodbuddyMicroserviceProto/Restaurant"
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+func TestLiftExpiredSuspensions(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	ended := now.Add(-time.Hour)
+	endsNow := now
+	later := now.Add(time.Hour)
+
+	repo := newFakeRepo()
+	for id, endsAt := range map[string]*time.Time{
+		"ban_ended":      &ended,
+		"ban_ends_now":   &endsNow,
+		"ban_stuck":      &ended,
+		"ban_running":    &later,
+		"ban_indefinite": nil,
+	} {
+		repo.bans[id] = &model.BanRecord{ID: id, RestaurantID: "rest_1", EndsAt: endsAt}
+	}
+	repo.failLift = map[string]bool{"ban_stuck": true}
+	s := NewRestaurantService(repo, "", LogNotifier{})
+
+	// One ban failing to lift must not keep the others in place.
+	if err := s.LiftExpiredSuspensions(now); err == nil {
+		t.Error("LiftExpiredSuspensions() = nil, want an error for the ban that failed")
+	}
+	for id, wantLifted := range map[string]bool{
+		"ban_ended":      true,
+		"ban_ends_now":   true,
+		"ban_stuck":      false,
+		"ban_running":    false,
+		"ban_indefinite": false,
+	} {
+		ban := repo.bans[id]
+		if lifted := ban.LiftedAt != nil; lifted != wantLifted {
+			t.Errorf("%s lifted = %v, want %v", id, lifted, wantLifted)
+			continue
+		}
+		if wantLifted && ban.LiftedBy != model.ActorSystem {
+			t.Errorf("%s lifted by %q, want %q", id, ban.LiftedBy, model.ActorSystem)
+		}
+	}
+	if repo.actor != model.ActorSystem {
+		t.Errorf("writes attributed to %q, want %q", repo.actor, model.ActorSystem)
+	}
+
+	// The stuck ban is retried on the next run.
+	repo.failLift = nil
+	if err := s.LiftExpiredSuspensions(now); err != nil {
+		t.Fatalf("second run error = %v", err)
+	}
+	if repo.bans["ban_stuck"].LiftedAt == nil {
+		t.Error("ban_stuck was not lifted on the second run")
+	}
+}
+
+func TestBanRestaurantActor(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor string
+		want  string
+	}{
+		{name: "authenticated admin", actor: "admin_7", want: "admin_7"},
+		{name: "no caller", want: model.ActorAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.restaurants["rest_1"] = &model.Restaurant{ID: "rest_1"}
+			s := NewRestaurantService(repo, "", LogNotifier{})
+
+			ctx := context.Background()
+			if tt.actor != "" {
+				ctx = model.WithActor(ctx, tt.actor)
+			}
+			if _, err := s.BanRestaurant(ctx, &restaurantPb.BanRestaurantRequest{RestaurantId: "rest_1", Reason: "hygiene"}); err != nil {
+				t.Fatalf("BanRestaurant() error = %v", err)
+			}
+			if len(repo.bans) != 1 {
+				t.Fatalf("%d bans recorded, want 1", len(repo.bans))
+			}
+			for _, ban := range repo.bans {
+				if ban.IssuedBy != tt.want {
+					t.Errorf("ban issued by %q, want %q", ban.IssuedBy, tt.want)
+				}
+			}
+
+			if _, err := s.UnbanRestaurant(ctx, &restaurantPb.UnbanRestaurantRequest{RestaurantId: "rest_1"}); err != nil {
+				t.Fatalf("UnbanRestaurant() error = %v", err)
+			}
+			for _, ban := range repo.bans {
+				if ban.LiftedBy != tt.want {
+					t.Errorf("ban lifted by %q, want %q", ban.LiftedBy, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBanRestaurantNotFound(t *testing.T) {
+	s := NewRestaurantService(newFakeRepo(), "", LogNotifier{})
+
+	_, err := s.BanRestaurant(context.Background(), &restaurantPb.BanRestaurantRequest{RestaurantId: "rest_missing"})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("BanRestaurant() code = %v, want NotFound (%v)", got, err)
+	}
+	_, err = s.UnbanRestaurant(context.Background(), &restaurantPb.UnbanRestaurantRequest{RestaurantId: "rest_missing"})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("UnbanRestaurant() code = %v, want NotFound (%v)", got, err)
+	}
+}
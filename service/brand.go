@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// Brand handlers. A brand owner logs in once and then picks which branch to act on;
+// branches created through the brand carry no login of their own.
+
+// CreateBrand registers a brand owner account.
+func (s *RestaurantService) CreateBrand(ctx context.Context, name, ownerEmail, password string) (string, error) {
+	if existing, err := s.repo.GetBrandByEmail(ownerEmail); err == nil && existing != nil {
+		return "", model.ErrEmailAlreadyExists
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	brand := &model.Brand{
+		ID:           fmt.Sprintf("brand_%s", uuid.New().String()),
+		Name:         name,
+		OwnerEmail:   ownerEmail,
+		PasswordHash: string(hashedPassword),
+	}
+
+	if err := s.repo.CreateBrand(brand); err != nil {
+		return "", err
+	}
+	return brand.ID, nil
+}
+
+// BrandLogin authenticates a brand owner and returns the brand with its branches
+// so the client can offer a branch switcher.
+func (s *RestaurantService) BrandLogin(ctx context.Context, ownerEmail, password string) (*model.Brand, []*model.Restaurant, error) {
+	brand, err := s.repo.GetBrandByEmail(ownerEmail)
+	if err != nil {
+		return nil, nil, model.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(brand.PasswordHash), []byte(password)); err != nil {
+		return nil, nil, model.ErrInvalidCredentials
+	}
+
+	branches, err := s.repo.GetBranches(brand.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get branches: %v", err)
+	}
+	return brand, branches, nil
+}
+
+// SwitchBranch confirms that restaurantID is a branch of brandID and returns it,
+// so the caller can issue a session scoped to that branch.
+func (s *RestaurantService) SwitchBranch(ctx context.Context, brandID, restaurantID string) (*model.Restaurant, error) {
+	restaurant, err := s.repo.GetRestaurantByID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if restaurant.BrandID != brandID {
+		return nil, model.ErrBranchNotInBrand
+	}
+
+	if restaurant.IsBanned {
+		return nil, model.ErrRestaurantIsBanned
+	}
+	return restaurant, nil
+}
+
+// AddBranch creates a new branch restaurant under a brand.
+func (s *RestaurantService) AddBranch(ctx context.Context, brandID string, branch *model.Restaurant) (string, error) {
+	if _, err := s.repo.GetBrandByID(brandID); err != nil {
+		return "", err
+	}
+
+	branch.ID = fmt.Sprintf("rest_%s", uuid.New().String())
+	branch.BrandID = brandID
+	// Branches are run through the brand and have no login of their own
+	branch.OwnerEmail = nil
+	branch.PasswordHash = ""
+	branch.Status = model.StatusPendingReview
+
+	if err := s.repo.WithContext(ctx).CreateRestaurant(branch); err != nil {
+		return "", fmt.Errorf("failed to create branch: %v", err)
+	}
+	return branch.ID, nil
+}
+
+// AttachBranch moves an existing standalone restaurant under a brand. The caller
+// must prove ownership of the restaurant with its current credentials.
+func (s *RestaurantService) AttachBranch(ctx context.Context, brandID, restaurantEmail, restaurantPassword string) (string, error) {
+	if _, err := s.repo.GetBrandByID(brandID); err != nil {
+		return "", err
+	}
+
+	restaurant, err := s.repo.GetRestaurantByEmail(restaurantEmail)
+	if err != nil {
+		return "", model.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(restaurant.PasswordHash), []byte(restaurantPassword)); err != nil {
+		return "", model.ErrInvalidCredentials
+	}
+	if restaurant.BrandID != "" {
+		return "", model.ErrAlreadyInBrand
+	}
+
+	if err := s.repo.WithContext(ctx).SetRestaurantBrand(restaurant.ID, brandID); err != nil {
+		return "", err
+	}
+	return restaurant.ID, nil
+}
+
+// AddBrandMenuItem adds an entry to the brand's master menu.
+func (s *RestaurantService) AddBrandMenuItem(ctx context.Context, item *model.BrandMenuItem) (string, error) {
+	if err := item.Validate(); err != nil {
+		return "", err
+	}
+	if _, err := s.repo.GetBrandByID(item.BrandID); err != nil {
+		return "", err
+	}
+
+	item.ID = fmt.Sprintf("bmi_%s", uuid.New().String())
+	if err := s.repo.AddBrandMenuItem(item); err != nil {
+		return "", err
+	}
+	return item.ID, nil
+}
+
+// CopyBrandMenuToBranches copies the master menu to the given branches, or to
+// every branch when branchIDs is empty. Prices in overrides win over the master
+// price for that branch; existing copies keep their stock. Banned branches are
+// left alone when copying to every branch, and refused when named.
+func (s *RestaurantService) CopyBrandMenuToBranches(ctx context.Context, brandID string, branchIDs []string, overrides model.BranchPriceOverrides) error {
+	if err := overrides.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetBrandByID(brandID); err != nil {
+		return err
+	}
+
+	menu, err := s.repo.GetBrandMenu(brandID)
+	if err != nil {
+		return fmt.Errorf("failed to get brand menu: %v", err)
+	}
+
+	branches, err := s.repo.GetBranches(brandID)
+	if err != nil {
+		return fmt.Errorf("failed to get branches: %v", err)
+	}
+
+	targets := make(map[string]bool, len(branches))
+	for _, b := range branches {
+		targets[b.ID] = len(branchIDs) == 0
+	}
+	for _, id := range branchIDs {
+		if _, ok := targets[id]; !ok {
+			return model.ErrBranchNotInBrand
+		}
+		targets[id] = true
+	}
+
+	var products []*model.Product
+	for _, b := range branches {
+		if !targets[b.ID] {
+			continue
+		}
+		if _, err := s.getUnbannedRestaurant(b.ID); err != nil {
+			if errors.Is(err, model.ErrRestaurantIsBanned) && len(branchIDs) == 0 {
+				continue
+			}
+			return err
+		}
+		for _, item := range menu {
+			products = append(products, &model.Product{
+				ID:              fmt.Sprintf("prod_%s", uuid.New().String()),
+				RestaurantID:    b.ID,
+				Name:            item.Name,
+				Description:     item.Description,
+				Price:           overrides.Price(b.ID, item),
+				Category:        item.Category,
+				BrandMenuItemID: item.ID,
+			})
+		}
+	}
+
+	if err := s.repo.WithContext(ctx).SyncBranchProducts(products, actorOr(ctx, brandID)); err != nil {
+		return fmt.Errorf("failed to copy brand menu: %w", err)
+	}
+	return nil
+}
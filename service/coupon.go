@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// CreateCoupon adds a coupon code to a restaurant. Codes are case-insensitive.
+func (s *RestaurantService) CreateCoupon(ctx context.Context, coupon *model.Coupon) (string, error) {
+	coupon.Code = model.NormalizeCouponCode(coupon.Code)
+	if err := coupon.Validate(); err != nil {
+		return "", err
+	}
+
+	if _, err := s.getUnbannedRestaurant(coupon.RestaurantID); err != nil {
+		return "", err
+	}
+
+	if _, err := s.repo.GetCouponByCode(coupon.RestaurantID, coupon.Code); err == nil {
+		return "", model.ErrInvalidCoupon
+	}
+
+	coupon.ID = fmt.Sprintf("coupon_%s", uuid.New().String())
+	coupon.Redemptions = 0
+	coupon.Active = true
+	if err := s.repo.CreateCoupon(coupon); err != nil {
+		return "", err
+	}
+	return coupon.ID, nil
+}
+
+// ValidateCoupon checks whether the customer can use the code on an order of
+// orderTotal and returns the discount it would give. It does not reserve a use.
+func (s *RestaurantService) ValidateCoupon(ctx context.Context, restaurantID, code, customerID string, orderTotal model.Amount) (model.Amount, error) {
+	coupon, err := s.checkCoupon(restaurantID, code, customerID, orderTotal)
+	if err != nil {
+		return 0, err
+	}
+	return coupon.Discount(orderTotal), nil
+}
+
+// RedeemCoupon uses the code on an order and returns the discount. The caps are
+// re-checked inside the redemption transaction along with the coupon itself, so
+// a coupon cannot be redeemed more often than allowed, or after it was switched
+// off, even when checkouts race.
+func (s *RestaurantService) RedeemCoupon(ctx context.Context, restaurantID, code, customerID, orderID string, orderTotal model.Amount) (model.Amount, error) {
+	if orderID == "" || customerID == "" {
+		return 0, model.ErrInvalidCoupon
+	}
+
+	coupon, err := s.checkCoupon(restaurantID, code, customerID, orderTotal)
+	if err != nil {
+		return 0, err
+	}
+
+	redemption := &model.CouponRedemption{
+		CouponID:   coupon.ID,
+		CustomerID: customerID,
+		OrderID:    orderID,
+	}
+	if err := s.repo.RedeemCoupon(coupon, redemption, orderTotal, time.Now()); err != nil {
+		return 0, err
+	}
+	return redemption.Discount, nil
+}
+
+// checkCoupon runs the validity checks shared by ValidateCoupon and RedeemCoupon.
+func (s *RestaurantService) checkCoupon(restaurantID, code, customerID string, orderTotal model.Amount) (*model.Coupon, error) {
+	coupon, err := s.repo.GetCouponByCode(restaurantID, model.NormalizeCouponCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := coupon.Check(orderTotal, time.Now()); err != nil {
+		return nil, err
+	}
+	if coupon.UsageCap > 0 && coupon.Redemptions >= coupon.UsageCap {
+		return nil, model.ErrCouponExhausted
+	}
+
+	if coupon.PerUserCap > 0 && customerID != "" {
+		used, err := s.repo.CountCouponRedemptions(coupon.ID, customerID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(coupon.PerUserCap) {
+			return nil, model.ErrCouponUserLimit
+		}
+	}
+	return coupon, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// AddDeliveryZone registers a radius or polygon delivery zone for a restaurant.
+func (s *RestaurantService) AddDeliveryZone(ctx context.Context, zone *model.DeliveryZone) (string, error) {
+	if err := zone.Validate(); err != nil {
+		return "", err
+	}
+
+	restaurant, err := s.repo.GetRestaurantByID(zone.RestaurantID)
+	if err != nil {
+		return "", err
+	}
+	// A radius zone is meaningless until the restaurant has a location to centre it on.
+	if zone.ZoneType == model.DeliveryZoneRadius && restaurant.Latitude == 0 && restaurant.Longitude == 0 {
+		return "", model.ErrInvalidCoordinates
+	}
+
+	zone.ID = fmt.Sprintf("zone_%s", uuid.New().String())
+	if err := s.repo.AddDeliveryZone(zone); err != nil {
+		return "", err
+	}
+	return zone.ID, nil
+}
+
+// GetDeliveryZones lists the delivery zones of a restaurant.
+func (s *RestaurantService) GetDeliveryZones(ctx context.Context, restaurantID string) ([]*model.DeliveryZone, error) {
+	return s.repo.GetDeliveryZones(restaurantID)
+}
+
+// DeleteDeliveryZone removes one of the restaurant's delivery zones.
+func (s *RestaurantService) DeleteDeliveryZone(ctx context.Context, restaurantID, zoneID string) error {
+	return s.repo.DeleteDeliveryZone(restaurantID, zoneID)
+}
+
+// CanDeliverTo returns the zone that serves (lat, lng) so the order service can
+// apply its minimum order and delivery fee before checkout. It fails with
+// model.ErrAddressNotServiceable when no zone covers the address.
+func (s *RestaurantService) CanDeliverTo(ctx context.Context, restaurantID string, lat, lng float64) (*model.DeliveryZone, error) {
+	if err := model.ValidateCoordinates(lat, lng); err != nil {
+		return nil, err
+	}
+
+	restaurant, err := s.getUnbannedRestaurant(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := s.repo.GetDeliveryZones(restaurantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery zones: %v", err)
+	}
+
+	var matching []*model.DeliveryZone
+	for _, z := range zones {
+		if z.Contains(restaurant, lat, lng) {
+			matching = append(matching, z)
+		}
+	}
+
+	zone := model.CheapestZone(matching)
+	if zone == nil {
+		return nil, model.ErrAddressNotServiceable
+	}
+	return zone, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// errorCodes maps domain errors to the gRPC code callers should see.
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{model.ErrRestaurantNotFound, codes.NotFound},
+	{model.ErrProductNotFound, codes.NotFound},
+	{model.ErrRestaurantIsBanned, codes.FailedPrecondition},
+	{model.ErrInsufficientStock, codes.FailedPrecondition},
+	{model.ErrInvalidStockOperation, codes.InvalidArgument},
+	{model.ErrStockNotEditable, codes.InvalidArgument},
+	{model.ErrInvalidAmount, codes.InvalidArgument},
+	{model.ErrInvalidCurrency, codes.InvalidArgument},
+	{model.ErrCurrencyChange, codes.FailedPrecondition},
+	{model.ErrInvalidTimezone, codes.InvalidArgument},
+	{model.ErrInvalidCoordinates, codes.InvalidArgument},
+	{model.ErrInvalidPageToken, codes.InvalidArgument},
+	{model.ErrInvalidPageSize, codes.InvalidArgument},
+	{model.ErrInvalidSort, codes.InvalidArgument},
+	{model.ErrInvalidPriceRange, codes.InvalidArgument},
+	{model.ErrEmptyUpdate, codes.InvalidArgument},
+	{model.ErrInvalidProductUpdate, codes.InvalidArgument},
+	{model.ErrInvalidRestaurantUpdate, codes.InvalidArgument},
+	{model.ErrProductOwnership, codes.PermissionDenied},
+	{model.ErrActorRequired, codes.Unauthenticated},
+	{model.ErrVersionRequired, codes.FailedPrecondition},
+	{model.ErrStaleVersion, codes.Aborted},
+}
+
+// toStatusError converts a domain error into a gRPC status error. Errors without
+// a mapping are passed through unchanged.
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	for _, m := range errorCodes {
+		if errors.Is(err, m.err) {
+			return status.Error(m.code, err.Error())
+		}
+	}
+	return err
+}
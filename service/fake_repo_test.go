@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"gorm.io/gorm"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+	"github.com/liju-github/FoodBuddyMicroserviceRestaurant/repository"
+)
+
+// fakeRepo is an in-memory repository for service tests. It keeps the rules the
+// real one enforces in SQL, such as the version check on conditional updates.
+// Methods a test does not need fall through to the nil embedded interface and
+// panic, so an unexpected query fails the test loudly.
+type fakeRepo struct {
+	repository.RestaurantRepository
+
+	restaurants map[string]*model.Restaurant
+	products    map[string]*model.Product
+	history     map[string][]*model.ProductVersion
+	bans        map[string]*model.BanRecord
+	appeals     map[string]*model.BanAppeal
+	reviews     map[string]*model.Review
+	transitions []*model.RestaurantStatusTransition
+
+	// actor is the actor on the context of the last WithContext call.
+	actor string
+	// failLift makes LiftBan fail for these ban IDs.
+	failLift map[string]bool
+
+	// beforeWrite runs before a conditional update, to simulate a concurrent edit.
+	beforeWrite func()
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		restaurants: map[string]*model.Restaurant{},
+		products:    map[string]*model.Product{},
+		history:     map[string][]*model.ProductVersion{},
+		bans:        map[string]*model.BanRecord{},
+		appeals:     map[string]*model.BanAppeal{},
+		reviews:     map[string]*model.Review{},
+	}
+}
+
+func (f *fakeRepo) WithContext(ctx context.Context) repository.RestaurantRepository {
+	f.actor = model.ActorFromContext(ctx)
+	return f
+}
+
+// Records are handed out as copies, as rows read from a database would be.
+
+func (f *fakeRepo) GetRestaurantByID(id string) (*model.Restaurant, error) {
+	r, ok := f.restaurants[id]
+	if !ok {
+		return nil, model.ErrRestaurantNotFound
+	}
+	c := *r
+	return &c, nil
+}
+
+func (f *fakeRepo) GetProductByID(productID string) (*model.Product, error) {
+	p, ok := f.products[productID]
+	if !ok || p.DeletedAt.Valid {
+		return nil, model.ErrProductNotFound
+	}
+	c := *p
+	return &c, nil
+}
+
+func (f *fakeRepo) UpdateRestaurantProfile(restaurant *model.Restaurant) error {
+	if f.beforeWrite != nil {
+		f.beforeWrite()
+	}
+	stored, ok := f.restaurants[restaurant.ID]
+	if !ok || stored.Version != restaurant.Version {
+		return model.ErrStaleVersion
+	}
+	restaurant.Version++
+	c := *restaurant
+	f.restaurants[restaurant.ID] = &c
+	return nil
+}
+
+func (f *fakeRepo) UpdateProductWithHistory(product *model.Product, actorID, note string) error {
+	if f.beforeWrite != nil {
+		f.beforeWrite()
+	}
+	stored, ok := f.products[product.ID]
+	if !ok || stored.Version != product.Version {
+		return model.ErrStaleVersion
+	}
+	product.Version++
+	c := *product
+	f.products[product.ID] = &c
+	v := model.NewProductVersion(product, actorID, note)
+	v.Version = int(product.Version)
+	f.history[product.ID] = append(f.history[product.ID], v)
+	return nil
+}
+
+func (f *fakeRepo) GetProductVersion(productID string, version int) (*model.ProductVersion, error) {
+	for _, v := range f.history[productID] {
+		if v.Version == version {
+			c := *v
+			return &c, nil
+		}
+	}
+	return nil, model.ErrProductVersionNotFound
+}
+
+func (f *fakeRepo) GetProductIncludingDeleted(productID string) (*model.Product, error) {
+	p, ok := f.products[productID]
+	if !ok {
+		return nil, model.ErrProductNotFound
+	}
+	c := *p
+	return &c, nil
+}
+
+func (f *fakeRepo) RestoreProduct(productID string) error {
+	p, ok := f.products[productID]
+	if !ok || !p.DeletedAt.Valid {
+		return model.ErrProductNotDeleted
+	}
+	p.DeletedAt = gorm.DeletedAt{}
+	p.Version++
+	return nil
+}
+
+func (f *fakeRepo) PurgeDeletedProducts(deletedBefore time.Time) (int64, error) {
+	var purged int64
+	for id, p := range f.products {
+		if p.DeletedAt.Valid && p.DeletedAt.Time.Before(deletedBefore) {
+			delete(f.products, id)
+			delete(f.history, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (f *fakeRepo) BanRestaurant(ban *model.BanRecord) error {
+	r, ok := f.restaurants[ban.RestaurantID]
+	if !ok {
+		return model.ErrRestaurantNotFound
+	}
+	r.IsBanned = true
+	f.bans[ban.ID] = ban
+	return nil
+}
+
+func (f *fakeRepo) UnbanRestaurant(restaurantID, liftedBy, note string, now time.Time) error {
+	r, ok := f.restaurants[restaurantID]
+	if !ok {
+		return model.ErrRestaurantNotFound
+	}
+	for _, b := range f.bans {
+		if b.RestaurantID == restaurantID && b.LiftedAt == nil {
+			if err := f.LiftBan(b.ID, liftedBy, note, now); err != nil {
+				return err
+			}
+		}
+	}
+	r.IsBanned = false
+	return nil
+}
+
+func (f *fakeRepo) GetExpiredBans(now time.Time) ([]*model.BanRecord, error) {
+	var expired []*model.BanRecord
+	for _, b := range f.bans {
+		if b.LiftedAt == nil && b.EndsAt != nil && !now.Before(*b.EndsAt) {
+			expired = append(expired, b)
+		}
+	}
+	return expired, nil
+}
+
+func (f *fakeRepo) LiftBan(banID, liftedBy, note string, now time.Time) error {
+	if f.failLift[banID] {
+		return errors.New("lock wait timeout exceeded")
+	}
+	b, ok := f.bans[banID]
+	if !ok {
+		return model.ErrBanNotFound
+	}
+	b.LiftedAt = &now
+	b.LiftedBy = liftedBy
+	b.LiftNote = note
+	return nil
+}
+
+func (f *fakeRepo) GetBanAppealByID(appealID string) (*model.BanAppeal, error) {
+	a, ok := f.appeals[appealID]
+	if !ok {
+		return nil, model.ErrAppealNotFound
+	}
+	c := *a
+	return &c, nil
+}
+
+func (f *fakeRepo) ReviewBanAppeal(appealID, status, reviewerID, comment, liftNote string, now time.Time) error {
+	a, ok := f.appeals[appealID]
+	if !ok {
+		return model.ErrAppealNotFound
+	}
+	if a.Status != model.AppealPending {
+		return model.ErrAppealAlreadyReviewed
+	}
+	a.Status = status
+	a.ReviewerID = reviewerID
+	a.ReviewedAt = &now
+	a.ReviewComment = comment
+	if status == model.AppealAccepted {
+		return f.LiftBan(a.BanID, reviewerID, liftNote, now)
+	}
+	return nil
+}
+
+func (f *fakeRepo) ModerateReview(reviewID, status, moderatorID, note string) error {
+	r, ok := f.reviews[reviewID]
+	if !ok {
+		return model.ErrReviewNotFound
+	}
+	r.Status = status
+	r.ModeratedBy = moderatorID
+	r.ModerationNote = note
+	return nil
+}
+
+func (f *fakeRepo) TransitionRestaurantStatus(transition *model.RestaurantStatusTransition) error {
+	r, ok := f.restaurants[transition.RestaurantID]
+	if !ok || r.Status != transition.FromStatus {
+		return model.ErrInvalidStatusChange
+	}
+	r.Status = transition.ToStatus
+	f.transitions = append(f.transitions, transition)
+	return nil
+}
+
+// headerStream captures the response headers a handler sets.
+type headerStream struct {
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return "" }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *headerStream) SetTrailer(metadata.MD) error { return nil }
+
+// rpcContext returns a context for calling a handler with the given request
+// metadata, and the stream its response headers end up on.
+func rpcContext(kv ...string) (context.Context, *headerStream) {
+	stream := &headerStream{}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+	return grpc.NewContextWithServerTransportStream(ctx, stream), stream
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// maxSearchRadiusKm caps nearby searches so a huge radius cannot scan the whole table.
+const maxSearchRadiusKm = 50.0
+
+// Latitude and longitude travel in gRPC metadata on RestaurantSignup and
+// EditRestaurant until the proto messages carry them.
+const (
+	latitudeHeader  = "x-latitude"
+	longitudeHeader = "x-longitude"
+)
+
+// requestLocation reads the restaurant's coordinates from the request metadata.
+// ok is false when neither is given; giving only one, or a value that is not a
+// valid coordinate, is model.ErrInvalidCoordinates.
+func requestLocation(ctx context.Context) (lat, lng float64, ok bool, err error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	lats, lngs := md.Get(latitudeHeader), md.Get(longitudeHeader)
+	if len(lats) == 0 && len(lngs) == 0 {
+		return 0, 0, false, nil
+	}
+	if len(lats) == 0 || len(lngs) == 0 {
+		return 0, 0, false, model.ErrInvalidCoordinates
+	}
+
+	lat, latErr := strconv.ParseFloat(lats[0], 64)
+	lng, lngErr := strconv.ParseFloat(lngs[0], 64)
+	if latErr != nil || lngErr != nil {
+		return 0, 0, false, model.ErrInvalidCoordinates
+	}
+	if err := model.ValidateCoordinates(lat, lng); err != nil {
+		return 0, 0, false, err
+	}
+	return lat, lng, true, nil
+}
+
+// SetRestaurantLocation stores the coordinates used by nearby search.
+func (s *RestaurantService) SetRestaurantLocation(ctx context.Context, restaurantID string, lat, lng float64) error {
+	if err := model.ValidateCoordinates(lat, lng); err != nil {
+		return err
+	}
+
+	if _, err := s.getUnbannedRestaurant(restaurantID); err != nil {
+		return err
+	}
+
+	if err := s.repo.WithContext(ctx).UpdateRestaurantLocation(restaurantID, lat, lng); err != nil {
+		return fmt.Errorf("failed to update restaurant location: %v", err)
+	}
+	return nil
+}
+
+// SearchNearbyRestaurants returns restaurants within radiusKm of (lat, lng),
+// closest first. Candidates come from an indexed bounding-box query and are then
+// filtered by exact haversine distance.
+func (s *RestaurantService) SearchNearbyRestaurants(ctx context.Context, lat, lng, radiusKm float64) ([]*model.NearbyRestaurant, error) {
+	if err := model.ValidateCoordinates(lat, lng); err != nil {
+		return nil, err
+	}
+	if radiusKm <= 0 || radiusKm > maxSearchRadiusKm {
+		return nil, model.ErrInvalidSearchRadius
+	}
+
+	candidates, err := s.repo.GetRestaurantsInBoundingBox(model.BoundingBoxAround(lat, lng, radiusKm))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search restaurants: %v", err)
+	}
+
+	var nearby []*model.NearbyRestaurant
+	for _, r := range candidates {
+		distance := model.HaversineKm(lat, lng, r.Latitude, r.Longitude)
+		if distance > radiusKm {
+			continue
+		}
+		nearby = append(nearby, &model.NearbyRestaurant{
+			Restaurant: r,
+			DistanceKm: distance,
+		})
+	}
+
+	sort.Slice(nearby, func(i, j int) bool {
+		return nearby[i].DistanceKm < nearby[j].DistanceKm
+	})
+
+	return nearby, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// GetProductHistory returns every recorded version of a product, newest first,
+// so support can see what a product looked like and cost at any point.
+func (s *RestaurantService) GetProductHistory(ctx context.Context, productID string) ([]*model.ProductVersion, error) {
+	if _, err := s.repo.GetProductIncludingDeleted(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetProductHistory(productID)
+}
+
+// RevertProduct restores the name, description, price and category of an
+// earlier history version. The revert is itself recorded as a new history
+// version. expectedVersion is the product version the revert is based on, as
+// for PatchProduct.
+func (s *RestaurantService) RevertProduct(ctx context.Context, restaurantID, productID string, expectedVersion int64, version int) (*model.Product, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.RestaurantID != restaurantID {
+		return nil, model.ErrProductNotFound
+	}
+	if _, err := s.getUnbannedRestaurant(restaurantID); err != nil {
+		return nil, err
+	}
+	if product.Version != expectedVersion {
+		return nil, model.ErrStaleVersion
+	}
+
+	target, err := s.repo.GetProductVersion(productID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	target.RestoreTo(product)
+	if err := s.repo.WithContext(ctx).UpdateProductWithHistory(product, actorOr(ctx, restaurantID), fmt.Sprintf("revert to version %d", version)); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+func TestRevertProduct(t *testing.T) {
+	tests := []struct {
+		name            string
+		restaurantID    string
+		banned          bool
+		expectedVersion int64
+		version         int
+		wantErr         error
+	}{
+		{name: "revert to an earlier version", restaurantID: "rest_1", expectedVersion: 2, version: 1},
+		{name: "stale expected version", restaurantID: "rest_1", expectedVersion: 1, version: 1, wantErr: model.ErrStaleVersion},
+		{name: "unknown version", restaurantID: "rest_1", expectedVersion: 2, version: 7, wantErr: model.ErrProductVersionNotFound},
+		{name: "another restaurant's product", restaurantID: "rest_2", expectedVersion: 2, version: 1, wantErr: model.ErrProductNotFound},
+		{name: "banned restaurant", restaurantID: "rest_1", banned: true, expectedVersion: 2, version: 1, wantErr: model.ErrRestaurantIsBanned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.restaurants["rest_1"] = &model.Restaurant{ID: "rest_1", IsBanned: tt.banned}
+			repo.restaurants["rest_2"] = &model.Restaurant{ID: "rest_2"}
+			repo.products["prod_1"] = &model.Product{ID: "prod_1", RestaurantID: "rest_1", Name: "Masala Chai", Price: 2500, Stock: 3, Version: 2}
+			repo.history["prod_1"] = []*model.ProductVersion{
+				{ProductID: "prod_1", Version: 1, Name: "Tea", Description: "Hot", Price: 2000, Stock: 10, Category: "drinks"},
+				{ProductID: "prod_1", Version: 2, Name: "Masala Chai", Price: 2500, Stock: 3},
+			}
+			s := NewRestaurantService(repo, "", LogNotifier{})
+
+			ctx := model.WithActor(context.Background(), "admin_7")
+			product, err := s.RevertProduct(ctx, tt.restaurantID, "prod_1", tt.expectedVersion, tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RevertProduct() error = %v, want %v", err, tt.wantErr)
+			}
+			history := repo.history["prod_1"]
+			if tt.wantErr != nil {
+				if len(history) != 2 {
+					t.Errorf("failed revert recorded history: %d versions", len(history))
+				}
+				return
+			}
+
+			stored := repo.products["prod_1"]
+			if stored.Name != "Tea" || stored.Description != "Hot" || stored.Price != 2000 || stored.Category != "drinks" {
+				t.Errorf("reverted product = %+v, want the version 1 listing", *stored)
+			}
+			if stored.Stock != 3 {
+				t.Errorf("stock = %d, a revert must keep the current stock", stored.Stock)
+			}
+			if product.Version != 3 {
+				t.Errorf("version = %d, want 3", product.Version)
+			}
+
+			// The revert is a new version of its own, not a rewrite of history.
+			if len(history) != 3 {
+				t.Fatalf("history has %d versions, want 3", len(history))
+			}
+			last := history[2]
+			if last.Version != 3 || last.Name != "Tea" || last.ActorID != "admin_7" || last.Note != "revert to version 1" {
+				t.Errorf("revert recorded as %+v", *last)
+			}
+		})
+	}
+}
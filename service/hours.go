@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// The operations below back the opening-hours RPCs. The shared proto does not carry
+// hours yet, so they are plain methods until the RestaurantService definition grows them.
+
+// SetOpeningHours replaces the weekly schedule of a restaurant.
+func (s *RestaurantService) SetOpeningHours(ctx context.Context, restaurantID string, hours []*model.OpeningHours) error {
+	if _, err := s.repo.GetRestaurantByID(restaurantID); err != nil {
+		return err
+	}
+
+	for _, h := range hours {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.SetOpeningHours(restaurantID, hours); err != nil {
+		return fmt.Errorf("failed to set opening hours: %v", err)
+	}
+	return nil
+}
+
+// AddHolidayClosure closes the restaurant for the given date (YYYY-MM-DD).
+func (s *RestaurantService) AddHolidayClosure(ctx context.Context, restaurantID, date, reason string) error {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return model.ErrInvalidHolidayDate
+	}
+
+	if _, err := s.repo.GetRestaurantByID(restaurantID); err != nil {
+		return err
+	}
+
+	closure := &model.HolidayClosure{
+		RestaurantID: restaurantID,
+		Date:         date,
+		Reason:       reason,
+	}
+	return s.repo.AddHolidayClosure(closure)
+}
+
+// RemoveHolidayClosure reopens a previously closed date.
+func (s *RestaurantService) RemoveHolidayClosure(ctx context.Context, restaurantID, date string) error {
+	return s.repo.DeleteHolidayClosure(restaurantID, date)
+}
+
+// SetRestaurantTimezone changes the timezone opening hours and price schedules
+// are read in. Names that time.LoadLocation cannot resolve are rejected.
+func (s *RestaurantService) SetRestaurantTimezone(ctx context.Context, restaurantID, timezone string) error {
+	if err := model.ValidateTimezone(timezone); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetRestaurantByID(restaurantID); err != nil {
+		return err
+	}
+	return s.repo.WithContext(ctx).SetRestaurantTimezone(restaurantID, timezone)
+}
+
+// SetAcceptingOrders toggles the temporary pause switch on a restaurant.
+func (s *RestaurantService) SetAcceptingOrders(ctx context.Context, restaurantID string, accepting bool) error {
+	if _, err := s.getUnbannedRestaurant(restaurantID); err != nil {
+		return err
+	}
+	return s.repo.WithContext(ctx).SetRestaurantPaused(restaurantID, !accepting)
+}
+
+// GetRestaurantOpenStatus computes whether a restaurant is open right now and when it next opens.
+func (s *RestaurantService) GetRestaurantOpenStatus(ctx context.Context, restaurantID string) (*model.OpenStatus, error) {
+	restaurant, err := s.repo.GetRestaurantByID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.openStatus(restaurant, time.Now())
+}
+
+func (s *RestaurantService) openStatus(restaurant *model.Restaurant, now time.Time) (*model.OpenStatus, error) {
+	hours, err := s.repo.GetOpeningHours(restaurant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opening hours: %v", err)
+	}
+
+	closures, err := s.repo.GetHolidayClosures(restaurant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday closures: %v", err)
+	}
+
+	status := model.ComputeOpenStatus(restaurant, hours, closures, now)
+	return &status, nil
+}
+
+// openStatuses computes the open status of several restaurants, loading their
+// hours and closures in one query each.
+func (s *RestaurantService) openStatuses(restaurants []*model.Restaurant, now time.Time) ([]model.OpenStatus, error) {
+	ids := make([]string, 0, len(restaurants))
+	for _, r := range restaurants {
+		ids = append(ids, r.ID)
+	}
+
+	hours, err := s.repo.GetOpeningHoursByRestaurantIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opening hours: %v", err)
+	}
+	closures, err := s.repo.GetHolidayClosuresByRestaurantIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday closures: %v", err)
+	}
+
+	statuses := make([]model.OpenStatus, 0, len(restaurants))
+	for _, r := range restaurants {
+		statuses = append(statuses, model.ComputeOpenStatus(r, hours[r.ID], closures[r.ID], now))
+	}
+	return statuses, nil
+}
+
+// sendOpenStatus returns open statuses as response headers until the proto
+// messages carry them. Listings send one value per restaurant under each key, in
+// the order of the response; x-next-open-at is empty while open or paused.
+func sendOpenStatus(ctx context.Context, statuses ...model.OpenStatus) {
+	md := metadata.MD{}
+	for _, status := range statuses {
+		next := ""
+		if !status.NextOpenAt.IsZero() {
+			next = status.NextOpenAt.UTC().Format(time.RFC3339)
+		}
+		md.Append("x-open-now", strconv.FormatBool(status.IsOpenNow))
+		md.Append("x-next-open-at", next)
+	}
+	_ = grpc.SetHeader(ctx, md)
+}
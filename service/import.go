@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// BulkImportProducts upserts a restaurant's menu from a CSV or JSON file. Every
+// row is checked first and all problems are reported by line; if there are any,
+// nothing is written. Otherwise the rows are upserted by SKU or name in one
+// transaction. A dry run does all of that and rolls it back, so owners can check
+// a file before committing to it.
+func (s *RestaurantService) BulkImportProducts(ctx context.Context, restaurantID, format string, data []byte, dryRun bool) (*model.ImportResult, error) {
+	restaurant, err := s.getUnbannedRestaurant(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, problems, err := model.ParseImport(format, data)
+	if err != nil {
+		return nil, err
+	}
+	items, invalid := model.ValidateImportRows(rows, restaurant.CurrencyCode())
+	problems = append(problems, invalid...)
+	if len(problems) > 0 {
+		sort.SliceStable(problems, func(i, j int) bool {
+			return problems[i].Line < problems[j].Line
+		})
+		return &model.ImportResult{DryRun: dryRun, Errors: problems}, nil
+	}
+
+	return s.repo.WithContext(ctx).ImportProducts(restaurantID, items, actorOr(ctx, restaurantID), dryRun)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// kycExpiryWarning is how long before expiry the owner is warned about a document.
+const kycExpiryWarning = 30 * 24 * time.Hour
+
+// SubmitKYCDocument stores the metadata of an uploaded compliance document for review.
+func (s *RestaurantService) SubmitKYCDocument(ctx context.Context, doc *model.KYCDocument) (string, error) {
+	if !model.ValidDocumentType(doc.DocType) || doc.Number == "" || doc.StorageRef == "" {
+		return "", model.ErrInvalidDocument
+	}
+	if doc.ExpiresAt != nil && !doc.ExpiresAt.After(time.Now()) {
+		return "", model.ErrInvalidDocument
+	}
+
+	if _, err := s.repo.GetRestaurantByID(doc.RestaurantID); err != nil {
+		return "", err
+	}
+
+	doc.ID = fmt.Sprintf("kyc_%s", uuid.New().String())
+	doc.Status = model.DocPending
+	doc.ReviewerID = ""
+	doc.ReviewComment = ""
+	doc.ReviewedAt = nil
+	doc.ExpiryNotifiedAt = nil
+
+	if err := s.repo.CreateKYCDocument(doc); err != nil {
+		return "", err
+	}
+	return doc.ID, nil
+}
+
+// GetKYCDocuments lists a restaurant's documents, newest first.
+func (s *RestaurantService) GetKYCDocuments(ctx context.Context, restaurantID string) ([]*model.KYCDocument, error) {
+	return s.repo.GetKYCDocuments(restaurantID)
+}
+
+// ReviewKYCDocument lets an admin verify or reject a pending document. The
+// decision is recorded against the authenticated caller.
+func (s *RestaurantService) ReviewKYCDocument(ctx context.Context, docID string, verify bool, comment string) error {
+	reviewerID, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+
+	status := model.DocRejected
+	if verify {
+		status = model.DocVerified
+	}
+	return s.repo.ReviewKYCDocument(docID, status, reviewerID, comment, time.Now())
+}
+
+// CheckKYCExpiry warns owners about documents close to expiry and marks expired
+// ones. Documents already replaced by a verified renewal are neither warned about
+// nor acted on beyond being marked expired. With autoSuspend set, a restaurant
+// whose food safety license has expired without a renewal is suspended
+// indefinitely through the regular ban mechanism; an admin lifts the ban once a
+// renewed license has been verified. A document that fails is logged and
+// skipped so the rest still get handled; the failures are returned together.
+func (s *RestaurantService) CheckKYCExpiry(ctx context.Context, now time.Time, autoSuspend bool) error {
+	var errs []error
+	fail := func(err error) {
+		log.Printf("KYC expiry check: %v", err)
+		errs = append(errs, err)
+	}
+
+	expiring, err := s.repo.GetExpiringKYCDocuments(now.Add(kycExpiryWarning))
+	if err != nil {
+		fail(fmt.Errorf("failed to get expiring documents: %v", err))
+	}
+
+	for _, doc := range expiring {
+		renewed, err := s.repo.HasValidKYCDocument(doc.RestaurantID, doc.DocType, doc.ID, now.Add(kycExpiryWarning))
+		if err != nil {
+			fail(fmt.Errorf("failed to check renewals of document %s: %v", doc.ID, err))
+			continue
+		}
+		if renewed {
+			// Nothing to warn about; marking it keeps it out of later runs
+			if err := s.repo.MarkKYCExpiryNotified(doc.ID, now); err != nil {
+				fail(fmt.Errorf("failed to mark document %s as notified: %v", doc.ID, err))
+			}
+			continue
+		}
+
+		body := fmt.Sprintf("Your %s (%s) expires on %s. Please upload a renewed document.",
+			doc.DocType, doc.Number, doc.ExpiresAt.Format("2006-01-02"))
+		if err := s.notifier.Notify(doc.RestaurantID, "KYC document expiring", body); err != nil {
+			log.Printf("Failed to notify %s about document %s: %v", doc.RestaurantID, doc.ID, err)
+			continue
+		}
+		if err := s.repo.MarkKYCExpiryNotified(doc.ID, now); err != nil {
+			fail(fmt.Errorf("failed to mark document %s as notified: %v", doc.ID, err))
+		}
+	}
+
+	expired, err := s.repo.GetExpiredKYCDocuments(now)
+	if err != nil {
+		fail(fmt.Errorf("failed to get expired documents: %v", err))
+	}
+
+	repo := s.repo.WithContext(model.WithActor(ctx, model.ActorSystem))
+	for _, doc := range expired {
+		var ban *model.BanRecord
+		if autoSuspend && doc.DocType == model.DocFoodSafetyLicense {
+			renewed, err := s.repo.HasValidKYCDocument(doc.RestaurantID, doc.DocType, doc.ID, now)
+			if err != nil {
+				fail(fmt.Errorf("failed to check renewals of document %s: %v", doc.ID, err))
+				continue
+			}
+			if !renewed {
+				reason := fmt.Sprintf("food safety license %s expired on %s", doc.Number, doc.ExpiresAt.Format("2006-01-02"))
+				ban = newBanRecord(doc.RestaurantID, model.ActorSystem, model.BanCategoryCompliance, reason, 0, now)
+			}
+		}
+
+		if err := repo.ExpireKYCDocument(doc.ID, ban); err != nil {
+			fail(fmt.Errorf("failed to expire document %s: %v", doc.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StartKYCExpiryJob runs CheckKYCExpiry every interval until ctx is done.
+func (s *RestaurantService) StartKYCExpiryJob(ctx context.Context, interval time.Duration, autoSuspend bool) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := s.CheckKYCExpiry(ctx, now, autoSuspend); err != nil {
+					log.Printf("KYC expiry job failed: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// SetRestaurantCurrency changes the currency prices are stored in. Stored amounts
+// carry no currency of their own, so switching would silently reprice every
+// product; it is only allowed while the restaurant has no products.
+func (s *RestaurantService) SetRestaurantCurrency(ctx context.Context, restaurantID, currency string) error {
+	if _, err := model.CurrencyExponent(currency); err != nil {
+		return err
+	}
+
+	restaurant, err := s.repo.GetRestaurantByID(restaurantID)
+	if err != nil {
+		return err
+	}
+	if restaurant.CurrencyCode() == currency {
+		return nil
+	}
+
+	return s.repo.WithContext(ctx).SetRestaurantCurrency(restaurantID, currency)
+}
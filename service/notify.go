@@ -0,0 +1,17 @@
+package service
+
+import "log"
+
+// Notifier delivers messages to restaurant owners. The service has no mail or push
+// integration of its own, so deployments plug one in here.
+type Notifier interface {
+	Notify(restaurantID, subject, body string) error
+}
+
+// LogNotifier writes notifications to the service log.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(restaurantID, subject, body string) error {
+	log.Printf("Notification for %s: %s: %s", restaurantID, subject, body)
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// GetAllProducts, GetRestaurantProductsByID and GetAllRestaurantWithProducts
+// page through ListProducts and ListRestaurantsWithProducts. Their proto
+// requests are still empty, so the options travel in request metadata and the
+// next page token comes back as a response header until the messages carry them.
+const (
+	pageSizeHeader      = "x-page-size"
+	pageTokenHeader     = "x-page-token"
+	sortHeader          = "x-sort"
+	categoryHeader      = "x-category"
+	minPriceHeader      = "x-min-price"
+	maxPriceHeader      = "x-max-price"
+	inStockOnlyHeader   = "x-in-stock-only"
+	pincodeHeader       = "x-pincode"
+	localityHeader      = "x-locality"
+	nextPageTokenHeader = "x-next-page-token"
+)
+
+// productListOptions reads the product listing options from the request
+// metadata. Prices are major units of currency, like everywhere else on the
+// protobuf boundary.
+func productListOptions(ctx context.Context, currency string) (model.ProductListOptions, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	opts := model.ProductListOptions{
+		PageToken:   firstValue(md, pageTokenHeader),
+		Sort:        firstValue(md, sortHeader),
+		Category:    firstValue(md, categoryHeader),
+		InStockOnly: firstValue(md, inStockOnlyHeader) == "true",
+		Pincode:     firstValue(md, pincodeHeader),
+		Locality:    firstValue(md, localityHeader),
+	}
+
+	var err error
+	if opts.PageSize, err = pageSize(md); err != nil {
+		return opts, err
+	}
+	for _, p := range []struct {
+		header string
+		dst    *model.Amount
+	}{
+		{minPriceHeader, &opts.MinPrice},
+		{maxPriceHeader, &opts.MaxPrice},
+	} {
+		raw := firstValue(md, p.header)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return opts, model.ErrInvalidPriceRange
+		}
+		if *p.dst, err = model.ToMinor(value, currency); err != nil {
+			return opts, model.ErrInvalidPriceRange
+		}
+	}
+	return opts, nil
+}
+
+// restaurantListOptions reads the restaurant listing options from the request metadata.
+func restaurantListOptions(ctx context.Context) (model.RestaurantListOptions, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	opts := model.RestaurantListOptions{
+		PageToken: firstValue(md, pageTokenHeader),
+		Sort:      firstValue(md, sortHeader),
+		Pincode:   firstValue(md, pincodeHeader),
+		Locality:  firstValue(md, localityHeader),
+	}
+	var err error
+	opts.PageSize, err = pageSize(md)
+	return opts, err
+}
+
+// pageSize reads the requested page size; zero lets the listing use its default.
+func pageSize(md metadata.MD) (int, error) {
+	raw := firstValue(md, pageSizeHeader)
+	if raw == "" {
+		return 0, nil
+	}
+	size, err := strconv.Atoi(raw)
+	if err != nil || size < 0 {
+		return 0, model.ErrInvalidPageSize
+	}
+	return size, nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// sendNextPageToken returns the token for the following page as a response
+// header. It is empty on the last page.
+func sendNextPageToken(ctx context.Context, token string) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(nextPageTokenHeader, token))
+}
+
+// ListProducts returns one page of visible products. Set opts.RestaurantID to
+// page through a single restaurant's menu. Products carry their effective price,
+// while the price filter and sort use the listed one; see ProductListOptions.
+func (s *RestaurantService) ListProducts(ctx context.Context, opts model.ProductListOptions) (*model.ProductPage, error) {
+	if opts.MinPrice < 0 || opts.MaxPrice < 0 || (opts.MaxPrice > 0 && opts.MinPrice > opts.MaxPrice) {
+		return nil, model.ErrInvalidPriceRange
+	}
+	if opts.Sort == "" {
+		opts.Sort = model.SortName
+	}
+	opts.PageSize = model.NormalizePageSize(opts.PageSize)
+
+	page, err := s.repo.ListProducts(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyEffectivePrices(page.Products); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ListRestaurantsWithProducts returns one page of visible restaurants along with
+// their products, loaded for the whole page in a single query.
+func (s *RestaurantService) ListRestaurantsWithProducts(ctx context.Context, opts model.RestaurantListOptions) ([]*model.RestaurantWithProducts, string, error) {
+	if opts.Sort == "" {
+		opts.Sort = model.SortName
+	}
+	opts.PageSize = model.NormalizePageSize(opts.PageSize)
+
+	page, err := s.repo.ListRestaurants(opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ids := make([]string, 0, len(page.Restaurants))
+	for _, r := range page.Restaurants {
+		ids = append(ids, r.ID)
+	}
+	products, err := s.repo.GetProductsByRestaurantIDs(ids)
+	if err != nil {
+		return nil, "", err
+	}
+	var all []*model.Product
+	for _, list := range products {
+		all = append(all, list...)
+	}
+	if err := s.applyEffectivePrices(all); err != nil {
+		return nil, "", err
+	}
+
+	result := make([]*model.RestaurantWithProducts, 0, len(page.Restaurants))
+	for _, r := range page.Restaurants {
+		result = append(result, &model.RestaurantWithProducts{Restaurant: r, Products: products[r.ID]})
+	}
+	return result, page.NextPageToken, nil
+}
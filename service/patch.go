@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// PatchRestaurant applies a partial profile edit. Every set field is validated
+// before anything is written. version is the restaurant version the edit is based
+// on; if the restaurant has changed since, it fails with model.ErrStaleVersion.
+// It returns the new version.
+func (s *RestaurantService) PatchRestaurant(ctx context.Context, restaurantID string, version int64, patch model.RestaurantPatch) (int64, error) {
+	if err := patch.Validate(); err != nil {
+		return 0, err
+	}
+
+	restaurant, err := s.repo.GetRestaurantByID(restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	if restaurant.Version != version {
+		return 0, model.ErrStaleVersion
+	}
+
+	patch.ApplyTo(restaurant)
+	if err := s.repo.WithContext(ctx).UpdateRestaurantProfile(restaurant); err != nil {
+		return 0, err
+	}
+	return restaurant.Version, nil
+}
+
+// PatchProduct applies a partial edit to one of the restaurant's products and
+// records the result in the product's history. A product can never be moved to
+// another restaurant: a restaurantID other than the owner's is refused. Like
+// PatchRestaurant it checks version and returns the new one.
+func (s *RestaurantService) PatchProduct(ctx context.Context, restaurantID, productID string, version int64, patch model.ProductPatch) (int64, error) {
+	if err := patch.Validate(); err != nil {
+		return 0, err
+	}
+
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return 0, err
+	}
+	if product.RestaurantID != restaurantID {
+		return 0, model.ErrProductOwnership
+	}
+	if _, err := s.getUnbannedRestaurant(restaurantID); err != nil {
+		return 0, err
+	}
+	if product.Version != version {
+		return 0, model.ErrStaleVersion
+	}
+
+	patch.ApplyTo(product)
+	// Without a named caller the edit is attributed to the owning restaurant's account.
+	if err := s.repo.WithContext(ctx).UpdateProductWithHistory(product, actorOr(ctx, restaurantID), "edit"); err != nil {
+		return 0, err
+	}
+	return product.Version, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// AddPriceSchedule plans a price change or a recurring happy hour for a product.
+// It returns the schedule ID.
+func (s *RestaurantService) AddPriceSchedule(ctx context.Context, restaurantID string, schedule *model.PriceSchedule) (uint, error) {
+	if schedule.StartsAt.IsZero() {
+		schedule.StartsAt = time.Now()
+	}
+	if err := schedule.Validate(); err != nil {
+		return 0, err
+	}
+
+	if err := s.checkProductOwner(restaurantID, schedule.ProductID); err != nil {
+		return 0, err
+	}
+
+	schedule.ID = 0
+	if err := s.repo.CreatePriceSchedule(schedule); err != nil {
+		return 0, err
+	}
+	return schedule.ID, nil
+}
+
+// GetPriceSchedules lists every schedule of a product, past ones included.
+func (s *RestaurantService) GetPriceSchedules(ctx context.Context, productID string) ([]*model.PriceSchedule, error) {
+	return s.repo.GetPriceSchedules(productID)
+}
+
+// DeletePriceSchedule cancels a schedule of one of the restaurant's products.
+func (s *RestaurantService) DeletePriceSchedule(ctx context.Context, restaurantID string, scheduleID uint) error {
+	schedule, err := s.repo.GetPriceScheduleByID(scheduleID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkProductOwner(restaurantID, schedule.ProductID); err != nil {
+		return err
+	}
+	return s.repo.DeletePriceSchedule(scheduleID)
+}
+
+// checkProductOwner fails unless the product belongs to the restaurant and the
+// restaurant is not banned.
+func (s *RestaurantService) checkProductOwner(restaurantID, productID string) error {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return err
+	}
+	if product.RestaurantID != restaurantID {
+		return model.ErrProductNotFound
+	}
+	_, err = s.getUnbannedRestaurant(restaurantID)
+	return err
+}
+
+// applyEffectivePrices sets EffectivePrice on each product to what it costs right
+// now. Timezones are only looked up for restaurants whose products have a
+// schedule running, so catalogs without schedules cost a single extra query.
+func (s *RestaurantService) applyEffectivePrices(products []*model.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	now := time.Now()
+
+	ids := make([]string, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	schedules, err := s.repo.GetCurrentPriceSchedules(ids, now)
+	if err != nil {
+		return err
+	}
+
+	var restaurantIDs []string
+	for _, p := range products {
+		if len(schedules[p.ID]) > 0 {
+			restaurantIDs = append(restaurantIDs, p.RestaurantID)
+		}
+	}
+	timezones, err := s.repo.GetRestaurantTimezones(dedupe(restaurantIDs))
+	if err != nil {
+		return err
+	}
+
+	locations := make(map[string]*time.Location, len(timezones))
+	for id, tz := range timezones {
+		locations[id] = (&model.Restaurant{Timezone: tz}).Location()
+	}
+
+	for _, p := range products {
+		price := p.Price
+		if list := schedules[p.ID]; len(list) > 0 {
+			loc, ok := locations[p.RestaurantID]
+			if !ok {
+				loc = (&model.Restaurant{}).Location()
+			}
+			price = model.EffectivePrice(p, list, loc, now)
+		}
+		p.EffectivePrice = &price
+	}
+	return nil
+}
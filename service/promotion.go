@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// CreatePromotion adds an automatic offer to a restaurant's catalog.
+func (s *RestaurantService) CreatePromotion(ctx context.Context, promotion *model.Promotion) (string, error) {
+	if promotion.StartsAt.IsZero() {
+		promotion.StartsAt = time.Now()
+	}
+	if err := promotion.Validate(); err != nil {
+		return "", err
+	}
+
+	if _, err := s.getUnbannedRestaurant(promotion.RestaurantID); err != nil {
+		return "", err
+	}
+
+	if promotion.RuleType == model.PromoBuyXGetY {
+		products, err := s.repo.GetProductsByIDs([]string{promotion.BuyProductID, promotion.GetProductID})
+		if err != nil {
+			return "", err
+		}
+		for _, p := range products {
+			if p.RestaurantID != promotion.RestaurantID {
+				return "", model.ErrProductNotFound
+			}
+		}
+		if len(products) == 0 || (promotion.BuyProductID != promotion.GetProductID && len(products) != 2) {
+			return "", model.ErrProductNotFound
+		}
+	}
+
+	promotion.ID = fmt.Sprintf("promo_%s", uuid.New().String())
+	promotion.Active = true
+	if err := s.repo.CreatePromotion(promotion); err != nil {
+		return "", err
+	}
+	return promotion.ID, nil
+}
+
+// GetPromotions lists all promotions of a restaurant.
+func (s *RestaurantService) GetPromotions(ctx context.Context, restaurantID string) ([]*model.Promotion, error) {
+	return s.repo.GetPromotions(restaurantID, false)
+}
+
+// SetPromotionActive switches a promotion on or off.
+func (s *RestaurantService) SetPromotionActive(ctx context.Context, restaurantID, promotionID string, active bool) error {
+	promotion, err := s.repo.GetPromotionByID(promotionID)
+	if err != nil {
+		return err
+	}
+	if promotion.RestaurantID != restaurantID {
+		return model.ErrPromotionNotFound
+	}
+	if _, err := s.getUnbannedRestaurant(restaurantID); err != nil {
+		return err
+	}
+	return s.repo.SetPromotionActive(promotionID, active)
+}
+
+// QuotePriceWithPromotions prices a cart like QuotePrice and then applies the
+// restaurant's live promotions for this customer. The quote lists which
+// promotions applied with the discount each gave, and why the others did not.
+func (s *RestaurantService) QuotePriceWithPromotions(ctx context.Context, restaurantID, customerID string, items []model.LineItem) (*model.PriceQuote, error) {
+	quote, products, err := s.buildQuote(restaurantID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions, err := s.repo.GetPromotions(restaurantID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %v", err)
+	}
+
+	ids := make([]string, 0, len(promotions))
+	for _, p := range promotions {
+		ids = append(ids, p.ID)
+	}
+	usage, err := s.repo.CountPromotionUsage(ids, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count promotion usage: %v", err)
+	}
+
+	now := time.Now()
+	var eligible []*model.Promotion
+	for _, p := range promotions {
+		switch {
+		case !p.IsLive(now):
+			quote.Skipped = append(quote.Skipped, &model.SkippedPromotion{PromotionID: p.ID, Name: p.Name, Reason: "not running at this time"})
+		case p.PerCustomerLimit > 0 && usage[p.ID] >= int64(p.PerCustomerLimit):
+			quote.Skipped = append(quote.Skipped, &model.SkippedPromotion{PromotionID: p.ID, Name: p.Name, Reason: "usage limit reached for this customer"})
+		default:
+			eligible = append(eligible, p)
+		}
+	}
+
+	applied, skipped := model.ApplyPromotions(quote, products, eligible)
+	quote.Promotions = applied
+	quote.Skipped = append(quote.Skipped, skipped...)
+	quote.ComputeTaxes()
+	return quote, nil
+}
+
+// RecordPromotionUsage counts the promotions of a placed order against the
+// customer's per-customer limits. The order service calls it at checkout with
+// the promotion IDs from the quote; it fails with model.ErrPromotionLimitReached
+// if another order used up a limit since, and nothing is recorded then. Calling it
+// again for the same order records nothing new.
+func (s *RestaurantService) RecordPromotionUsage(ctx context.Context, promotionIDs []string, customerID, orderID string) error {
+	if orderID == "" {
+		return model.ErrPromotionNeedsOrder
+	}
+	usages := make([]*model.PromotionUsage, 0, len(promotionIDs))
+	for _, id := range promotionIDs {
+		usages = append(usages, &model.PromotionUsage{
+			PromotionID: id,
+			CustomerID:  customerID,
+			OrderID:     orderID,
+		})
+	}
+	return s.repo.RecordPromotionUsage(usages)
+}
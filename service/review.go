@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// SubmitReview records a customer's rating for a restaurant or one of its products.
+// The order reference stops the same order from being reviewed twice.
+func (s *RestaurantService) SubmitReview(ctx context.Context, review *model.Review) (string, error) {
+	if review.Rating < 1 || review.Rating > 5 {
+		return "", model.ErrInvalidRating
+	}
+	if review.CustomerID == "" || review.OrderID == "" {
+		return "", model.ErrInvalidReview
+	}
+
+	if _, err := s.repo.GetRestaurantByID(review.RestaurantID); err != nil {
+		return "", err
+	}
+	if review.ProductID != "" {
+		product, err := s.repo.GetProductByID(review.ProductID)
+		if err != nil {
+			return "", err
+		}
+		if product.RestaurantID != review.RestaurantID {
+			return "", model.ErrProductNotFound
+		}
+	}
+
+	review.ID = fmt.Sprintf("rev_%s", uuid.New().String())
+	review.Status = model.ReviewVisible
+	review.OwnerReply = ""
+	review.OwnerRepliedAt = nil
+
+	if err := s.repo.CreateReview(review); err != nil {
+		return "", err
+	}
+	return review.ID, nil
+}
+
+// GetReviews lists visible reviews of a restaurant, or of one product when productID is set.
+func (s *RestaurantService) GetReviews(ctx context.Context, restaurantID, productID string) ([]*model.Review, error) {
+	return s.repo.GetReviews(restaurantID, productID, false)
+}
+
+// ReplyToReview stores the restaurant owner's public reply to a review.
+func (s *RestaurantService) ReplyToReview(ctx context.Context, restaurantID, reviewID, reply string) error {
+	review, err := s.repo.GetReviewByID(reviewID)
+	if err != nil {
+		return err
+	}
+	if review.RestaurantID != restaurantID {
+		return model.ErrReviewNotFound
+	}
+	return s.repo.ReplyToReview(reviewID, reply, time.Now())
+}
+
+// ModerateReview hides or restores a review. Hidden reviews are left out of the
+// listings and the rating aggregates. The authenticated caller is recorded as the
+// moderator.
+func (s *RestaurantService) ModerateReview(ctx context.Context, reviewID string, hide bool, note string) error {
+	moderatorID, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+
+	status := model.ReviewVisible
+	if hide {
+		status = model.ReviewHidden
+	}
+	return s.repo.ModerateReview(reviewID, status, moderatorID, note)
+}
+
+// GetRestaurantRating returns the aggregate rating kept on the restaurant row.
+func (s *RestaurantService) GetRestaurantRating(ctx context.Context, restaurantID string) (*model.RatingSummary, error) {
+	restaurant, err := s.repo.GetRestaurantByID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	summary := restaurant.Rating()
+	return &summary, nil
+}
+
+// GetProductRating returns the aggregate rating kept on the product row.
+func (s *RestaurantService) GetProductRating(ctx context.Context, productID string) (*model.RatingSummary, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	summary := product.Rating()
+	return &summary, nil
+}
+
+// sendRatings returns rating aggregates as response headers until the proto
+// messages carry them. Listings send one value per item under each key, in the
+// order of the response.
+func sendRatings(ctx context.Context, ratings ...model.RatingSummary) {
+	md := metadata.MD{}
+	for _, r := range ratings {
+		md.Append("x-rating-average", strconv.FormatFloat(r.Average, 'f', 2, 64))
+		md.Append("x-rating-count", strconv.FormatInt(r.Count, 10))
+	}
+	_ = grpc.SetHeader(ctx, md)
+}
+
+// sendProductRatings sends the ratings of products in the order of the response.
+func sendProductRatings(ctx context.Context, products []*model.Product) {
+	ratings := make([]model.RatingSummary, 0, len(products))
+	for _, p := range products {
+		ratings = append(ratings, p.Rating())
+	}
+	sendRatings(ctx, ratings...)
+}
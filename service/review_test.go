@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+func TestModerateReview(t *testing.T) {
+	tests := []struct {
+		name       string
+		actor      string
+		hide       bool
+		wantErr    error
+		wantStatus string
+	}{
+		{name: "hidden by the caller", actor: "mod_3", hide: true, wantStatus: model.ReviewHidden},
+		{name: "restored by the caller", actor: "mod_3", wantStatus: model.ReviewVisible},
+		{name: "no authenticated caller", hide: true, wantErr: model.ErrActorRequired, wantStatus: model.ReviewVisible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.reviews["review_1"] = &model.Review{ID: "review_1", Status: model.ReviewVisible}
+			s := NewRestaurantService(repo, "", LogNotifier{})
+
+			ctx := context.Background()
+			if tt.actor != "" {
+				ctx = model.WithActor(ctx, tt.actor)
+			}
+			err := s.ModerateReview(ctx, "review_1", tt.hide, "spam")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ModerateReview() error = %v, want %v", err, tt.wantErr)
+			}
+			review := repo.reviews["review_1"]
+			if review.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", review.Status, tt.wantStatus)
+			}
+			if review.ModeratedBy != tt.actor {
+				t.Errorf("moderated by %q, want %q", review.ModeratedBy, tt.actor)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// Search finds restaurants and products by name, description, category and
+// locality, ranked by relevance. The last word is matched as a prefix so the
+// endpoint can back a typeahead.
+func (s *RestaurantService) Search(ctx context.Context, query model.SearchQuery) ([]*model.SearchHit, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if len(model.SearchTerms(query.Text)) == 0 {
+		return nil, model.ErrEmptySearchQuery
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+
+	hits, err := s.repo.Search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []*model.Product
+	for _, h := range hits {
+		if h.Product != nil {
+			products = append(products, h.Product)
+		}
+	}
+	if err := s.applyEffectivePrices(products); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -14,12 +15,16 @@ import (
 
 type RestaurantService struct {
 	restaurantPb.UnimplementedRestaurantServiceServer
-	repo repository.RestaurantRepository
+	repo         repository.RestaurantRepository
+	appealSecret []byte
+	notifier     Notifier
 }
 
-func NewRestaurantService(repo repository.RestaurantRepository) *RestaurantService {
+func NewRestaurantService(repo repository.RestaurantRepository, appealSecret string, notifier Notifier) *RestaurantService {
 	return &RestaurantService{
-		repo: repo,
+		repo:         repo,
+		appealSecret: []byte(appealSecret),
+		notifier:     notifier,
 	}
 }
 
@@ -36,10 +41,15 @@ func (s *RestaurantService) RestaurantSignup(ctx context.Context, req *restauran
 		return nil, fmt.Errorf("failed to hash password: %v", err)
 	}
 
+	lat, lng, hasLocation, err := requestLocation(ctx)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
 	// Create restaurant
 	restaurant := &model.Restaurant{
 		ID:           fmt.Sprintf("rest_%s", uuid.New().String()),
-		OwnerEmail:   req.OwnerEmail,
+		OwnerEmail:   &req.OwnerEmail,
 		PasswordHash: string(hashedPassword),
 		Name:         req.RestaurantName,
 		PhoneNumber:  req.PhoneNumber,
@@ -47,9 +57,17 @@ func (s *RestaurantService) RestaurantSignup(ctx context.Context, req *restauran
 		Locality:     req.Address.Locality,
 		State:        req.Address.State,
 		Pincode:      req.Address.Pincode,
+		Status:       model.StatusPendingReview,
+	}
+	if hasLocation {
+		restaurant.Latitude = lat
+		restaurant.Longitude = lng
 	}
+	// A self-signup has no authenticated caller yet, so the restaurant created itself
+	restaurant.CreatedBy = actorOr(ctx, restaurant.ID)
+	restaurant.UpdatedBy = restaurant.CreatedBy
 
-	if err := s.repo.CreateRestaurant(restaurant); err != nil {
+	if err := s.repo.WithContext(ctx).CreateRestaurant(restaurant); err != nil {
 		return nil, fmt.Errorf("failed to create restaurant: %v", err)
 	}
 
@@ -79,23 +97,50 @@ func (s *RestaurantService) RestaurantLogin(ctx context.Context, req *restaurant
 	}, nil
 }
 
+// EditRestaurant changes only the fields the request carries: proto3 cannot tell
+// an empty value from an absent one, so empty strings, a zero phone number and a
+// missing address leave the stored values alone. The request metadata must carry
+// the version the edit is based on; see versionHeader.
 func (s *RestaurantService) EditRestaurant(ctx context.Context, req *restaurantPb.EditRestaurantRequest) (*restaurantPb.EditRestaurantResponse, error) {
-
-	restaurant, err := s.repo.GetRestaurantByID(req.RestaurantId)
+	var patch model.RestaurantPatch
+	if req.RestaurantName != "" {
+		patch.Name = &req.RestaurantName
+	}
+	if req.PhoneNumber != 0 {
+		patch.PhoneNumber = &req.PhoneNumber
+	}
+	if addr := req.Address; addr != nil {
+		if addr.StreetName != "" {
+			patch.StreetName = &addr.StreetName
+		}
+		if addr.Locality != "" {
+			patch.Locality = &addr.Locality
+		}
+		if addr.State != "" {
+			patch.State = &addr.State
+		}
+		if addr.Pincode != "" {
+			patch.Pincode = &addr.Pincode
+		}
+	}
+	lat, lng, hasLocation, err := requestLocation(ctx)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
+	}
+	if hasLocation {
+		patch.Latitude = &lat
+		patch.Longitude = &lng
 	}
 
-	restaurant.Name = req.RestaurantName
-	restaurant.PhoneNumber = req.PhoneNumber
-	restaurant.StreetName = req.Address.StreetName
-	restaurant.Locality = req.Address.Locality
-	restaurant.State = req.Address.State
-	restaurant.Pincode = req.Address.Pincode
-
-	if err := s.repo.UpdateRestaurant(restaurant); err != nil {
-		return nil, fmt.Errorf("failed to update restaurant: %v", err)
+	version, err := expectedVersion(ctx)
+	if err != nil {
+		return nil, toStatusError(err)
 	}
+	newVersion, err := s.PatchRestaurant(ctx, req.RestaurantId, version, patch)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	sendVersion(ctx, newVersion)
 
 	return &restaurantPb.EditRestaurantResponse{
 		Message: "Restaurant updated successfully",
@@ -103,22 +148,26 @@ func (s *RestaurantService) EditRestaurant(ctx context.Context, req *restaurantP
 }
 
 func (s *RestaurantService) GetRestaurantProductsByID(ctx context.Context, req *restaurantPb.GetRestaurantProductsByIDRequest) (*restaurantPb.GetRestaurantProductsByIDResponse, error) {
-	products, err := s.repo.GetProductsByRestaurantID(req.RestaurantId)
+	restaurant, err := s.getUnbannedRestaurant(req.RestaurantId)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
+	}
+
+	opts, err := productListOptions(ctx, restaurant.CurrencyCode())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	opts.RestaurantID = req.RestaurantId
+	page, err := s.ListProducts(ctx, opts)
+	if err != nil {
+		return nil, toStatusError(err)
 	}
+	sendNextPageToken(ctx, page.NextPageToken)
+	sendProductRatings(ctx, page.Products)
 
 	var pbProducts []*restaurantPb.Product
-	for _, p := range products {
-		pbProducts = append(pbProducts, &restaurantPb.Product{
-			ProductId:    p.ID,
-			RestaurantId: p.RestaurantID,
-			Name:         p.Name,
-			Description:  p.Description,
-			Price:        p.Price,
-			Stock:        p.Stock,
-			Category:     p.Category,
-		})
+	for _, p := range page.Products {
+		pbProducts = append(pbProducts, toPbProduct(p, restaurant.CurrencyCode()))
 	}
 
 	return &restaurantPb.GetRestaurantProductsByIDResponse{
@@ -128,29 +177,38 @@ func (s *RestaurantService) GetRestaurantProductsByID(ctx context.Context, req *
 }
 
 func (s *RestaurantService) GetAllRestaurantWithProducts(ctx context.Context, req *restaurantPb.GetAllRestaurantAndProductsRequest) (*restaurantPb.GetAllRestaurantWithProductsResponse, error) {
-	restaurants, err := s.repo.GetAllRestaurants()
+	opts, err := restaurantListOptions(ctx)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
+	}
+	entries, nextPageToken, err := s.ListRestaurantsWithProducts(ctx, opts)
+	if err != nil {
+		return nil, toStatusError(err)
 	}
+	sendNextPageToken(ctx, nextPageToken)
 
-	var pbRestaurants []*restaurantPb.RestaurantWithProducts
+	restaurants := make([]*model.Restaurant, 0, len(entries))
+	for _, e := range entries {
+		restaurants = append(restaurants, e.Restaurant)
+	}
+
+	statuses, err := s.openStatuses(restaurants, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	sendOpenStatus(ctx, statuses...)
+	ratings := make([]model.RatingSummary, 0, len(restaurants))
 	for _, r := range restaurants {
-		products, err := s.repo.GetProductsByRestaurantID(r.ID)
-		if err != nil {
-			continue
-		}
+		ratings = append(ratings, r.Rating())
+	}
+	sendRatings(ctx, ratings...)
 
+	var pbRestaurants []*restaurantPb.RestaurantWithProducts
+	for _, e := range entries {
+		r := e.Restaurant
 		var pbProducts []*restaurantPb.Product
-		for _, p := range products {
-			pbProducts = append(pbProducts, &restaurantPb.Product{
-				ProductId:    p.ID,
-				RestaurantId: p.RestaurantID,
-				Name:         p.Name,
-				Description:  p.Description,
-				Price:        p.Price,
-				Stock:        p.Stock,
-				Category:     p.Category,
-			})
+		for _, p := range e.Products {
+			pbProducts = append(pbProducts, toPbProduct(p, r.CurrencyCode()))
 		}
 
 		pbRestaurants = append(pbRestaurants, &restaurantPb.RestaurantWithProducts{
@@ -174,19 +232,33 @@ func (s *RestaurantService) GetAllRestaurantWithProducts(ctx context.Context, re
 }
 
 func (s *RestaurantService) AddProduct(ctx context.Context, req *restaurantPb.AddProductRequest) (*restaurantPb.AddProductResponse, error) {
+	restaurant, err := s.getUnbannedRestaurant(req.RestaurantId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	price, err := model.ToMinor(req.Price, restaurant.CurrencyCode())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if price < 0 {
+		return nil, toStatusError(model.ErrInvalidAmount)
+	}
 
 	product := &model.Product{
 		ID:           fmt.Sprintf("prod_%s", uuid.New().String()),
 		RestaurantID: req.RestaurantId,
 		Name:         req.Name,
 		Description:  req.Description,
-		Price:        req.Price,
+		Price:        price,
 		Stock:        req.Stock,
 		Category:     req.Category,
+		CreatedBy:    actorOr(ctx, req.RestaurantId),
+		UpdatedBy:    actorOr(ctx, req.RestaurantId),
 	}
 
-	if err := s.repo.AddProduct(product); err != nil {
-		return nil, fmt.Errorf("failed to add product: %v", err)
+	if err := s.repo.WithContext(ctx).AddProduct(product, restaurant.CurrencyCode()); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to add product: %w", err))
 	}
 
 	return &restaurantPb.AddProductResponse{
@@ -195,23 +267,58 @@ func (s *RestaurantService) AddProduct(ctx context.Context, req *restaurantPb.Ad
 	}, nil
 }
 
+// EditProduct changes only the fields the request carries; empty strings and a
+// zero price are treated as absent. Stock is ignored, as it changes through the
+// stock RPCs, and RestaurantId must name the current owner if it is set. As with
+// EditRestaurant, the request metadata must carry the expected version.
 func (s *RestaurantService) EditProduct(ctx context.Context, req *restaurantPb.EditProductRequest) (*restaurantPb.EditProductResponse, error) {
-
 	product, err := s.repo.GetProductByID(req.ProductId)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
+	}
+
+	restaurantID := req.RestaurantId
+	if restaurantID == "" {
+		restaurantID = product.RestaurantID
 	}
 
-	product.Name = req.Name
-	product.Description = req.Description
-	product.Price = req.Price
-	product.Stock = req.Stock
-	product.Category = req.Category
-	product.RestaurantID = req.RestaurantId
+	// Stock only moves through the stock RPCs, where changes are relative and
+	// cannot overwrite a concurrent order's
+	if req.Stock != 0 {
+		return nil, toStatusError(model.ErrStockNotEditable)
+	}
+
+	var patch model.ProductPatch
+	if req.Name != "" {
+		patch.Name = &req.Name
+	}
+	if req.Description != "" {
+		patch.Description = &req.Description
+	}
+	if req.Category != "" {
+		patch.Category = &req.Category
+	}
+	if req.Price != 0 {
+		restaurant, err := s.getUnbannedRestaurant(product.RestaurantID)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		price, err := model.ToMinor(req.Price, restaurant.CurrencyCode())
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		patch.Price = &price
+	}
 
-	if err := s.repo.UpdateProduct(product); err != nil {
-		return nil, fmt.Errorf("failed to update product: %v", err)
+	version, err := expectedVersion(ctx)
+	if err != nil {
+		return nil, toStatusError(err)
 	}
+	newVersion, err := s.PatchProduct(ctx, restaurantID, req.ProductId, version, patch)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	sendVersion(ctx, newVersion)
 
 	return &restaurantPb.EditProductResponse{
 		Message: "Product updated successfully",
@@ -219,22 +326,32 @@ func (s *RestaurantService) EditProduct(ctx context.Context, req *restaurantPb.E
 }
 
 func (s *RestaurantService) GetProductByID(ctx context.Context, req *restaurantPb.GetProductByIDRequest) (*restaurantPb.GetProductByIDResponse, error) {
-	product, err := s.repo.GetProductByID(req.ProductId)
+	// Deleted products still resolve here so past orders can show what was bought.
+	product, err := s.repo.GetProductIncludingDeleted(req.ProductId)
 	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	restaurant, err := s.getUnbannedRestaurant(product.RestaurantID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if err := s.applyEffectivePrices([]*model.Product{product}); err != nil {
 		return nil, err
 	}
+	sendVersion(ctx, product.Version)
+	sendAudit(ctx, product.CreatedAt, product.UpdatedAt, product.CreatedBy, product.UpdatedBy)
+	sendDeleted(ctx, product)
+	sendRatings(ctx, product.Rating())
 
+	message := "Product retrieved successfully"
+	if product.DeletedAt.Valid {
+		message = "Product retrieved successfully; it has been deleted"
+	}
 	return &restaurantPb.GetProductByIDResponse{
-		Product: &restaurantPb.Product{
-			ProductId:    product.ID,
-			RestaurantId: product.RestaurantID,
-			Name:         product.Name,
-			Description:  product.Description,
-			Price:        product.Price,
-			Stock:        product.Stock,
-			Category:     product.Category,
-		},
-		Message: "Product retrieved successfully",
+		Product: toPbProduct(product, restaurant.CurrencyCode()),
+		Message: message,
 	}, nil
 }
 
@@ -242,13 +359,15 @@ func (s *RestaurantService) DeleteProductByID(ctx context.Context, req *restaura
 
 	product, err := s.repo.GetProductByID(req.ProductId)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
-	product.RestaurantID = req.RestaurantId
+	if _, err := s.getUnbannedRestaurant(product.RestaurantID); err != nil {
+		return nil, toStatusError(err)
+	}
 
-	if err := s.repo.DeleteProduct(req.ProductId); err != nil {
-		return nil, err
+	if err := s.repo.WithContext(ctx).DeleteProduct(req.ProductId); err != nil {
+		return nil, toStatusError(err)
 	}
 
 	return &restaurantPb.DeleteProductByIDResponse{
@@ -257,9 +376,12 @@ func (s *RestaurantService) DeleteProductByID(ctx context.Context, req *restaura
 }
 
 func (s *RestaurantService) IncremenentProductStockByValue(ctx context.Context, req *restaurantPb.IncremenentProductStockByValueRequest) (*restaurantPb.IncremenentProductStockByValueResponse, error) {
+	if err := s.ensureProductRestaurantNotBanned(req.ProductId); err != nil {
+		return nil, toStatusError(err)
+	}
 
-	if err := s.repo.UpdateProductStock(req.ProductId, req.Value); err != nil {
-		return nil, err
+	if err := s.repo.WithContext(ctx).UpdateProductStock(req.ProductId, req.Value); err != nil {
+		return nil, toStatusError(err)
 	}
 
 	return &restaurantPb.IncremenentProductStockByValueResponse{
@@ -268,18 +390,21 @@ func (s *RestaurantService) IncremenentProductStockByValue(ctx context.Context,
 }
 
 func (s *RestaurantService) DecrementProductStockByValue(ctx context.Context, req *restaurantPb.DecrementProductStockByValueByValueRequest) (*restaurantPb.DecrementProductStockByValueResponse, error) {
+	if err := s.ensureProductRestaurantNotBanned(req.ProductId); err != nil {
+		return nil, toStatusError(err)
+	}
 
 	currentStock, err := s.repo.GetProductStock(req.ProductId)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	if currentStock < req.Value {
-		return nil, fmt.Errorf("insufficient stock")
+		return nil, toStatusError(model.ErrInsufficientStock)
 	}
 
-	if err := s.repo.UpdateProductStock(req.ProductId, -req.Value); err != nil {
-		return nil, err
+	if err := s.repo.WithContext(ctx).UpdateProductStock(req.ProductId, -req.Value); err != nil {
+		return nil, toStatusError(err)
 	}
 
 	return &restaurantPb.DecrementProductStockByValueResponse{
@@ -288,9 +413,9 @@ func (s *RestaurantService) DecrementProductStockByValue(ctx context.Context, re
 }
 
 func (s *RestaurantService) GetRestaurantIDviaProductID(ctx context.Context, req *restaurantPb.GetRestaurantIDviaProductIDRequest) (*restaurantPb.GetRestaurantIDviaProductIDResponse, error) {
-	product, err := s.repo.GetProductByID(req.ProductId)
+	product, err := s.repo.GetProductIncludingDeleted(req.ProductId)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	return &restaurantPb.GetRestaurantIDviaProductIDResponse{
@@ -302,7 +427,7 @@ func (s *RestaurantService) GetRestaurantIDviaProductID(ctx context.Context, req
 func (s *RestaurantService) GetStockByProductID(ctx context.Context, req *restaurantPb.GetStockByProductIDRequest) (*restaurantPb.GetStockByProductIDResponse, error) {
 	stock, err := s.repo.GetProductStock(req.ProductId)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	return &restaurantPb.GetStockByProductIDResponse{
@@ -312,8 +437,8 @@ func (s *RestaurantService) GetStockByProductID(ctx context.Context, req *restau
 }
 
 func (s *RestaurantService) BanRestaurant(ctx context.Context, req *restaurantPb.BanRestaurantRequest) (*restaurantPb.BanRestaurantResponse, error) {
-	if err := s.repo.BanRestaurant(req.RestaurantId, req.Reason); err != nil {
-		return nil, err
+	if _, err := s.SuspendRestaurant(ctx, req.RestaurantId, actorOr(ctx, model.ActorAdmin), model.BanCategoryOther, req.Reason, 0); err != nil {
+		return nil, toStatusError(err)
 	}
 
 	return &restaurantPb.BanRestaurantResponse{
@@ -322,8 +447,8 @@ func (s *RestaurantService) BanRestaurant(ctx context.Context, req *restaurantPb
 }
 
 func (s *RestaurantService) UnbanRestaurant(ctx context.Context, req *restaurantPb.UnbanRestaurantRequest) (*restaurantPb.UnbanRestaurantResponse, error) {
-	if err := s.repo.UnbanRestaurant(req.RestaurantId); err != nil {
-		return nil, err
+	if err := s.repo.WithContext(ctx).UnbanRestaurant(req.RestaurantId, actorOr(ctx, model.ActorAdmin), "", time.Now()); err != nil {
+		return nil, toStatusError(err)
 	}
 
 	return &restaurantPb.UnbanRestaurantResponse{
@@ -332,22 +457,32 @@ func (s *RestaurantService) UnbanRestaurant(ctx context.Context, req *restaurant
 }
 
 func (s *RestaurantService) GetAllProducts(ctx context.Context, req *restaurantPb.GetAllProductsRequest) (*restaurantPb.GetAllProductsResponse, error) {
-	products, err := s.repo.GetAllProducts()
+	// Products of several restaurants share one listing, so the price filter is
+	// read in the default currency.
+	opts, err := productListOptions(ctx, model.DefaultCurrency)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	page, err := s.ListProducts(ctx, opts)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	sendNextPageToken(ctx, page.NextPageToken)
+	products := page.Products
+
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.RestaurantID)
+	}
+	currencies, err := s.repo.GetRestaurantCurrencies(ids)
 	if err != nil {
 		return nil, err
 	}
+	sendProductRatings(ctx, products)
 
 	var pbProducts []*restaurantPb.Product
 	for _, product := range products {
-		pbProducts = append(pbProducts, &restaurantPb.Product{
-			ProductId:    product.ID,
-			RestaurantId: product.RestaurantID,
-			Name:         product.Name,
-			Description:  product.Description,
-			Price:        product.Price,
-			Stock:        product.Stock,
-			Category:     product.Category,
-		})
+		pbProducts = append(pbProducts, toPbProduct(product, currencies[product.RestaurantID]))
 	}
 
 	return &restaurantPb.GetAllProductsResponse{
@@ -385,6 +520,18 @@ func (s *RestaurantService) GetRestaurantByID(ctx context.Context, req *restaura
 		}, nil
 	}
 
+	status, err := s.openStatus(restaurant, time.Now())
+	if err != nil {
+		return &restaurantPb.GetRestaurantByIDResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to get restaurant: %v", err),
+		}, nil
+	}
+
+	sendVersion(ctx, restaurant.Version)
+	sendAudit(ctx, restaurant.CreatedAt, restaurant.UpdatedAt, restaurant.CreatedBy, restaurant.UpdatedBy)
+	sendOpenStatus(ctx, *status)
+	sendRatings(ctx, restaurant.Rating())
 	return &restaurantPb.GetRestaurantByIDResponse{
 		Success:        true,
 		Message:        "Restaurant found successfully",
@@ -401,3 +548,41 @@ func (s *RestaurantService) GetRestaurantByID(ctx context.Context, req *restaura
 		BanReason: restaurant.BanReason,
 	}, nil
 }
+
+// getUnbannedRestaurant loads a restaurant and fails with model.ErrRestaurantIsBanned
+// when it is banned, so its catalog can neither be read publicly nor changed.
+func (s *RestaurantService) getUnbannedRestaurant(restaurantID string) (*model.Restaurant, error) {
+	restaurant, err := s.repo.GetRestaurantByID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant.IsBanned {
+		return nil, model.ErrRestaurantIsBanned
+	}
+	return restaurant, nil
+}
+
+// ensureProductRestaurantNotBanned applies getUnbannedRestaurant to the owner of a product.
+func (s *RestaurantService) ensureProductRestaurantNotBanned(productID string) error {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return err
+	}
+	_, err = s.getUnbannedRestaurant(product.RestaurantID)
+	return err
+}
+
+// toPbProduct converts a product model into its protobuf form. Prices leave the
+// service as major units of the restaurant's currency, at the effective price
+// when price schedules have been applied.
+func toPbProduct(p *model.Product, currency string) *restaurantPb.Product {
+	return &restaurantPb.Product{
+		ProductId:    p.ID,
+		RestaurantId: p.RestaurantID,
+		Name:         p.Name,
+		Description:  p.Description,
+		Price:        p.SellingPrice().Major(currency),
+		Stock:        p.Stock,
+		Category:     p.Category,
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// sendDeleted marks a soft-deleted product in the response headers, so callers
+// resolving it for past orders can tell it is no longer on the menu.
+func sendDeleted(ctx context.Context, product *model.Product) {
+	if !product.DeletedAt.Valid {
+		return
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-deleted-at", product.DeletedAt.Time.UTC().Format(time.RFC3339)))
+}
+
+// RestoreProduct brings back a soft-deleted product of the restaurant.
+func (s *RestaurantService) RestoreProduct(ctx context.Context, restaurantID, productID string) error {
+	product, err := s.repo.GetProductIncludingDeleted(productID)
+	if err != nil {
+		return err
+	}
+	if product.RestaurantID != restaurantID {
+		return model.ErrProductNotFound
+	}
+	if _, err := s.getUnbannedRestaurant(restaurantID); err != nil {
+		return err
+	}
+	return s.repo.WithContext(ctx).RestoreProduct(productID)
+}
+
+// PurgeDeletedProducts permanently removes products that were deleted more than
+// retention ago.
+func (s *RestaurantService) PurgeDeletedProducts(now time.Time, retention time.Duration) (int64, error) {
+	return s.repo.PurgeDeletedProducts(now.Add(-retention))
+}
+
+// StartProductPurgeJob runs PurgeDeletedProducts every interval until ctx is done.
+func (s *RestaurantService) StartProductPurgeJob(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				purged, err := s.PurgeDeletedProducts(now, retention)
+				if err != nil {
+					log.Printf("Product purge job failed: %v", err)
+					continue
+				}
+				if purged > 0 {
+					log.Printf("Purged %d deleted products", purged)
+				}
+			}
+		}
+	}()
+}
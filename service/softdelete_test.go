@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+func deletedAt(t time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: t, Valid: true}
+}
+
+func TestRestoreProduct(t *testing.T) {
+	deleted := deletedAt(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name         string
+		restaurantID string
+		productID    string
+		banned       bool
+		wantErr      error
+	}{
+		{name: "deleted product", restaurantID: "rest_1", productID: "prod_deleted"},
+		{name: "product on the menu", restaurantID: "rest_1", productID: "prod_live", wantErr: model.ErrProductNotDeleted},
+		{name: "another restaurant's product", restaurantID: "rest_2", productID: "prod_deleted", wantErr: model.ErrProductNotFound},
+		{name: "unknown product", restaurantID: "rest_1", productID: "prod_missing", wantErr: model.ErrProductNotFound},
+		{name: "banned restaurant", restaurantID: "rest_1", productID: "prod_deleted", banned: true, wantErr: model.ErrRestaurantIsBanned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.restaurants["rest_1"] = &model.Restaurant{ID: "rest_1", IsBanned: tt.banned}
+			repo.restaurants["rest_2"] = &model.Restaurant{ID: "rest_2"}
+			repo.products["prod_live"] = &model.Product{ID: "prod_live", RestaurantID: "rest_1", Version: 1}
+			repo.products["prod_deleted"] = &model.Product{ID: "prod_deleted", RestaurantID: "rest_1", Version: 4, DeletedAt: deleted}
+			s := NewRestaurantService(repo, "", LogNotifier{})
+
+			err := s.RestoreProduct(context.Background(), tt.restaurantID, tt.productID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RestoreProduct() error = %v, want %v", err, tt.wantErr)
+			}
+			restored := !repo.products["prod_deleted"].DeletedAt.Valid
+			if restored != (tt.wantErr == nil && tt.productID == "prod_deleted") {
+				t.Errorf("prod_deleted restored = %v", restored)
+			}
+		})
+	}
+}
+
+func TestPurgeDeletedProducts(t *testing.T) {
+	now := time.Date(2026, 10, 31, 3, 0, 0, 0, time.UTC)
+	retention := 30 * 24 * time.Hour
+	cutoff := now.Add(-retention)
+
+	repo := newFakeRepo()
+	repo.products["prod_live"] = &model.Product{ID: "prod_live"}
+	repo.products["prod_old"] = &model.Product{ID: "prod_old", DeletedAt: deletedAt(cutoff.Add(-time.Second))}
+	repo.products["prod_at_cutoff"] = &model.Product{ID: "prod_at_cutoff", DeletedAt: deletedAt(cutoff)}
+	repo.products["prod_recent"] = &model.Product{ID: "prod_recent", DeletedAt: deletedAt(now.Add(-time.Hour))}
+	s := NewRestaurantService(repo, "", LogNotifier{})
+
+	purged, err := s.PurgeDeletedProducts(now, retention)
+	if err != nil {
+		t.Fatalf("PurgeDeletedProducts() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("purged %d products, want 1", purged)
+	}
+	for id, want := range map[string]bool{"prod_live": true, "prod_old": false, "prod_at_cutoff": true, "prod_recent": true} {
+		if _, kept := repo.products[id]; kept != want {
+			t.Errorf("%s kept = %v, want %v", id, kept, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// Admin onboarding handlers. New signups start in pending_review and only approved
+// restaurants appear in public listings.
+
+// TransitionRestaurantStatus moves a restaurant to a new onboarding state with a
+// comment, rejecting moves the state machine does not allow.
+func (s *RestaurantService) TransitionRestaurantStatus(ctx context.Context, restaurantID, toStatus, comment, actorID string) error {
+	restaurant, err := s.repo.GetRestaurantByID(restaurantID)
+	if err != nil {
+		return err
+	}
+
+	if !model.CanTransition(restaurant.Status, toStatus) {
+		return model.ErrInvalidStatusChange
+	}
+
+	return s.repo.WithContext(ctx).TransitionRestaurantStatus(&model.RestaurantStatusTransition{
+		RestaurantID: restaurantID,
+		FromStatus:   restaurant.Status,
+		ToStatus:     toStatus,
+		Comment:      comment,
+		ActorID:      actorID,
+	})
+}
+
+// ResubmitForReview lets an owner send a restaurant back for review after making
+// the requested changes.
+func (s *RestaurantService) ResubmitForReview(ctx context.Context, restaurantID, comment string) error {
+	return s.TransitionRestaurantStatus(ctx, restaurantID, model.StatusPendingReview, comment, restaurantID)
+}
+
+// GetRestaurantStatusHistory returns every onboarding transition of a restaurant, oldest first.
+func (s *RestaurantService) GetRestaurantStatusHistory(ctx context.Context, restaurantID string) ([]*model.RestaurantStatusTransition, error) {
+	return s.repo.GetRestaurantStatusHistory(restaurantID)
+}
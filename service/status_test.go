@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+func TestTransitionRestaurantStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       string
+		to         string
+		wantErr    error
+		wantStatus string
+	}{
+		{name: "approve", from: model.StatusPendingReview, to: model.StatusApproved, wantStatus: model.StatusApproved},
+		{name: "ask for changes", from: model.StatusPendingReview, to: model.StatusNeedsChanges, wantStatus: model.StatusNeedsChanges},
+		{name: "skip review", from: model.StatusNeedsChanges, to: model.StatusApproved, wantErr: model.ErrInvalidStatusChange, wantStatus: model.StatusNeedsChanges},
+		{name: "reopen a rejection", from: model.StatusRejected, to: model.StatusPendingReview, wantErr: model.ErrInvalidStatusChange, wantStatus: model.StatusRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.restaurants["rest_1"] = &model.Restaurant{ID: "rest_1", Status: tt.from}
+			s := NewRestaurantService(repo, "", LogNotifier{})
+
+			err := s.TransitionRestaurantStatus(context.Background(), "rest_1", tt.to, "checked", "admin_7")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TransitionRestaurantStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := repo.restaurants["rest_1"].Status; got != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantStatus)
+			}
+
+			if tt.wantErr != nil {
+				if len(repo.transitions) != 0 {
+					t.Errorf("refused transition was recorded: %+v", *repo.transitions[0])
+				}
+				return
+			}
+			if len(repo.transitions) != 1 {
+				t.Fatalf("%d transitions recorded, want 1", len(repo.transitions))
+			}
+			got := repo.transitions[0]
+			if got.FromStatus != tt.from || got.ToStatus != tt.to || got.ActorID != "admin_7" || got.Comment != "checked" {
+				t.Errorf("recorded %+v", *got)
+			}
+		})
+	}
+}
+
+func TestResubmitForReview(t *testing.T) {
+	repo := newFakeRepo()
+	repo.restaurants["rest_1"] = &model.Restaurant{ID: "rest_1", Status: model.StatusNeedsChanges}
+	s := NewRestaurantService(repo, "", LogNotifier{})
+
+	if err := s.ResubmitForReview(context.Background(), "rest_1", "menu photos added"); err != nil {
+		t.Fatalf("ResubmitForReview() error = %v", err)
+	}
+	if got := repo.restaurants["rest_1"].Status; got != model.StatusPendingReview {
+		t.Errorf("status = %q, want %q", got, model.StatusPendingReview)
+	}
+	// The owner resubmits, so the transition is theirs.
+	if got := repo.transitions[0].ActorID; got != "rest_1" {
+		t.Errorf("actor = %q, want rest_1", got)
+	}
+
+	// An approved restaurant has nothing to resubmit.
+	if err := s.ResubmitForReview(context.Background(), "rest_1", "again"); !errors.Is(err, model.ErrInvalidStatusChange) {
+		t.Errorf("second ResubmitForReview() error = %v, want ErrInvalidStatusChange", err)
+	}
+}
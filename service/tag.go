@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// ListTags returns the controlled vocabulary for a tag kind (cuisine, diet or allergen).
+func (s *RestaurantService) ListTags(ctx context.Context, kind string) ([]*model.Tag, error) {
+	return s.repo.GetTags(kind)
+}
+
+// SetRestaurantCuisines replaces the cuisine tags of a restaurant.
+func (s *RestaurantService) SetRestaurantCuisines(ctx context.Context, restaurantID string, tagIDs []string) error {
+	if _, err := s.getUnbannedRestaurant(restaurantID); err != nil {
+		return err
+	}
+	if err := s.checkTagKinds(tagIDs, model.TagCuisine); err != nil {
+		return err
+	}
+	return s.repo.SetRestaurantCuisines(restaurantID, dedupe(tagIDs))
+}
+
+// SetProductTags replaces the dietary and allergen tags of a product.
+func (s *RestaurantService) SetProductTags(ctx context.Context, productID string, tagIDs []string) error {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.getUnbannedRestaurant(product.RestaurantID); err != nil {
+		return err
+	}
+	if err := s.checkTagKinds(tagIDs, model.TagDiet, model.TagAllergen); err != nil {
+		return err
+	}
+	return s.repo.SetProductTags(productID, dedupe(tagIDs))
+}
+
+// ListRestaurantsByCuisine returns visible restaurants serving any of the given cuisines.
+func (s *RestaurantService) ListRestaurantsByCuisine(ctx context.Context, cuisines []string) ([]*model.Restaurant, error) {
+	if err := s.checkTagKinds(cuisines, model.TagCuisine); err != nil {
+		return nil, err
+	}
+	if len(cuisines) == 0 {
+		return s.repo.GetVisibleRestaurants()
+	}
+	return s.repo.GetRestaurantsByCuisines(dedupe(cuisines))
+}
+
+// ListProductsByTags returns visible products matching every diet and free of every
+// excluded allergen, so clients can filter server-side instead of downloading the
+// whole catalog.
+func (s *RestaurantService) ListProductsByTags(ctx context.Context, filter model.ProductTagFilter) ([]*model.Product, error) {
+	if err := s.checkTagKinds(filter.Diets, model.TagDiet); err != nil {
+		return nil, err
+	}
+	if err := s.checkTagKinds(filter.ExcludeAllergens, model.TagAllergen); err != nil {
+		return nil, err
+	}
+	filter.Diets = dedupe(filter.Diets)
+	filter.ExcludeAllergens = dedupe(filter.ExcludeAllergens)
+	products, err := s.repo.GetProductsByTags(filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyEffectivePrices(products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// checkTagKinds fails with model.ErrUnknownTag unless every ID names a tag of one of the kinds.
+func (s *RestaurantService) checkTagKinds(tagIDs []string, kinds ...string) error {
+	ids := dedupe(tagIDs)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tags, err := s.repo.GetTagsByIDs(ids)
+	if err != nil {
+		return err
+	}
+	if len(tags) != len(ids) {
+		return model.ErrUnknownTag
+	}
+
+	for _, t := range tags {
+		allowed := false
+		for _, k := range kinds {
+			if t.Kind == k {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return model.ErrUnknownTag
+		}
+	}
+	return nil
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
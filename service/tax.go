@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// maxTaxRateBasisPoints caps tax rates at 100%.
+const maxTaxRateBasisPoints = 10000
+
+// CreateTaxClass defines a tax rate that the restaurant's products can be assigned to.
+func (s *RestaurantService) CreateTaxClass(ctx context.Context, restaurantID, name string, rateBasisPoints int) (string, error) {
+	if rateBasisPoints < 0 || rateBasisPoints > maxTaxRateBasisPoints {
+		return "", model.ErrInvalidTaxRate
+	}
+
+	if _, err := s.repo.GetRestaurantByID(restaurantID); err != nil {
+		return "", err
+	}
+
+	taxClass := &model.TaxClass{
+		ID:              fmt.Sprintf("tax_%s", uuid.New().String()),
+		RestaurantID:    restaurantID,
+		Name:            name,
+		RateBasisPoints: rateBasisPoints,
+	}
+	if err := s.repo.CreateTaxClass(taxClass); err != nil {
+		return "", err
+	}
+	return taxClass.ID, nil
+}
+
+// GetTaxClasses lists the tax classes of a restaurant.
+func (s *RestaurantService) GetTaxClasses(ctx context.Context, restaurantID string) ([]*model.TaxClass, error) {
+	return s.repo.GetTaxClasses(restaurantID)
+}
+
+// SetProductTax assigns a product to one of its restaurant's tax classes and sets
+// whether its price already includes that tax. An empty taxClassID makes it untaxed.
+func (s *RestaurantService) SetProductTax(ctx context.Context, productID, taxClassID string, inclusive bool) error {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.getUnbannedRestaurant(product.RestaurantID); err != nil {
+		return err
+	}
+
+	if taxClassID != "" {
+		taxClass, err := s.repo.GetTaxClassByID(taxClassID)
+		if err != nil {
+			return err
+		}
+		if taxClass.RestaurantID != product.RestaurantID {
+			return model.ErrTaxClassNotFound
+		}
+	}
+
+	return s.repo.WithContext(ctx).SetProductTax(productID, taxClassID, inclusive)
+}
+
+// QuotePrice prices a cart from one restaurant: each line at the current product
+// price, after price schedules, then subtotal, tax per rate and total. Other
+// services use it so every invoice for the same cart comes out the same.
+func (s *RestaurantService) QuotePrice(ctx context.Context, restaurantID string, items []model.LineItem) (*model.PriceQuote, error) {
+	quote, _, err := s.buildQuote(restaurantID, items)
+	return quote, err
+}
+
+// buildQuote prices the lines and computes taxes. It also returns the products
+// by ID for callers that apply further rules to the cart.
+func (s *RestaurantService) buildQuote(restaurantID string, items []model.LineItem) (*model.PriceQuote, map[string]*model.Product, error) {
+	restaurant, err := s.getUnbannedRestaurant(restaurantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil, model.ErrInvalidLineItem
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, nil, model.ErrInvalidLineItem
+		}
+		ids = append(ids, item.ProductID)
+	}
+
+	products, err := s.repo.GetProductsByIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.applyEffectivePrices(products); err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]*model.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	taxClasses, err := s.repo.GetTaxClasses(restaurantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rates := make(map[string]int, len(taxClasses))
+	for _, tc := range taxClasses {
+		rates[tc.ID] = tc.RateBasisPoints
+	}
+
+	quote := &model.PriceQuote{Currency: restaurant.CurrencyCode()}
+	for _, item := range items {
+		product, ok := byID[item.ProductID]
+		if !ok || product.RestaurantID != restaurantID {
+			return nil, nil, model.ErrProductNotFound
+		}
+		quote.Lines = append(quote.Lines, &model.QuoteLine{
+			ProductID:       product.ID,
+			Name:            product.Name,
+			Quantity:        item.Quantity,
+			UnitPrice:       product.SellingPrice(),
+			LineTotal:       product.SellingPrice() * model.Amount(item.Quantity),
+			TaxInclusive:    product.TaxInclusive,
+			RateBasisPoints: rates[product.TaxClassID],
+		})
+	}
+
+	quote.ComputeTaxes()
+	return quote, byID, nil
+}
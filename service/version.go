@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+// versionHeader carries a record's version in gRPC metadata. Reads send it back
+// as a response header, and edits must echo the version they were based on, until
+// the proto messages carry the field themselves.
+const versionHeader = "x-record-version"
+
+// expectedVersion reads the version an edit was based on from the request metadata.
+func expectedVersion(ctx context.Context) (int64, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, model.ErrVersionRequired
+	}
+	values := md.Get(versionHeader)
+	if len(values) == 0 {
+		return 0, model.ErrVersionRequired
+	}
+	version, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, model.ErrVersionRequired
+	}
+	return version, nil
+}
+
+// sendVersion returns the record's version as a response header. Outside a gRPC
+// call there is nowhere to send it, which is not an error for the caller.
+func sendVersion(ctx context.Context, version int64) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(versionHeader, strconv.FormatInt(version, 10)))
+}
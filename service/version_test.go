@@ -0,0 +1,105 @@
+package service
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	restaurantPb "github.com/liju-github/CentralisedFoodbuddyMicroserviceProto/Restaurant"
+	model "github.com/liju-github/FoodBuddyMicroserviceRestaurant/models"
+)
+
+func TestEditRestaurantVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadata    []string
+		concurrent  bool // another edit lands between the read and the write
+		wantCode    codes.Code
+		wantVersion string
+	}{
+		{name: "current version", metadata: []string{versionHeader, "2"}, wantCode: codes.OK, wantVersion: "3"},
+		{name: "stale version", metadata: []string{versionHeader, "1"}, wantCode: codes.Aborted},
+		{name: "concurrent edit", metadata: []string{versionHeader, "2"}, concurrent: true, wantCode: codes.Aborted},
+		{name: "missing version", wantCode: codes.FailedPrecondition},
+		{name: "unreadable version", metadata: []string{versionHeader, "two"}, wantCode: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.restaurants["rest_1"] = &model.Restaurant{ID: "rest_1", Name: "Old", Version: 2}
+			if tt.concurrent {
+				repo.beforeWrite = func() { repo.restaurants["rest_1"].Version++ }
+			}
+			s := NewRestaurantService(repo, "", LogNotifier{})
+
+			ctx, stream := rpcContext(tt.metadata...)
+			_, err := s.EditRestaurant(ctx, &restaurantPb.EditRestaurantRequest{RestaurantId: "rest_1", RestaurantName: "New"})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("EditRestaurant() code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+
+			stored := repo.restaurants["rest_1"]
+			if tt.wantCode != codes.OK {
+				if stored.Name != "Old" {
+					t.Errorf("name = %q after a refused edit", stored.Name)
+				}
+				return
+			}
+			if stored.Name != "New" {
+				t.Errorf("name = %q, want New", stored.Name)
+			}
+			if got := stream.header.Get(versionHeader); len(got) != 1 || got[0] != tt.wantVersion {
+				t.Errorf("%s header = %v, want %s", versionHeader, got, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestEditProductVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadata    []string
+		concurrent  bool
+		wantCode    codes.Code
+		wantVersion string
+	}{
+		{name: "current version", metadata: []string{versionHeader, "5"}, wantCode: codes.OK, wantVersion: "6"},
+		{name: "stale version", metadata: []string{versionHeader, "4"}, wantCode: codes.Aborted},
+		{name: "concurrent edit", metadata: []string{versionHeader, "5"}, concurrent: true, wantCode: codes.Aborted},
+		{name: "missing version", wantCode: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.restaurants["rest_1"] = &model.Restaurant{ID: "rest_1", Version: 1}
+			repo.products["prod_1"] = &model.Product{ID: "prod_1", RestaurantID: "rest_1", Name: "Tea", Price: 2000, Version: 5}
+			if tt.concurrent {
+				repo.beforeWrite = func() { repo.products["prod_1"].Version++ }
+			}
+			s := NewRestaurantService(repo, "", LogNotifier{})
+
+			ctx, stream := rpcContext(tt.metadata...)
+			_, err := s.EditProduct(ctx, &restaurantPb.EditProductRequest{ProductId: "prod_1", Price: 25})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("EditProduct() code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+
+			stored := repo.products["prod_1"]
+			if tt.wantCode != codes.OK {
+				if stored.Price != 2000 || len(repo.history["prod_1"]) != 0 {
+					t.Errorf("refused edit changed the product: price %d, %d history entries", stored.Price, len(repo.history["prod_1"]))
+				}
+				return
+			}
+			if stored.Price != 2500 {
+				t.Errorf("price = %d, want 2500", stored.Price)
+			}
+			if got := stream.header.Get(versionHeader); len(got) != 1 || got[0] != tt.wantVersion {
+				t.Errorf("%s header = %v, want %s", versionHeader, got, tt.wantVersion)
+			}
+		})
+	}
+}